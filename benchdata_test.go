@@ -1,4 +1,4 @@
-package main
+package jsonvalidate
 
 var mdValid = `{"0":{"1":{"2":{"3":{"15a3f63c-50f8-4740-b8bd-58b2039958a6":"d8f2715d-c733-4f20-bf9c-9e026f25ccb9","4":{"5":{"004ab253-ea99-4d16-ba78-3d5130990063":"ec91137b-2efc-42f3-af96-f1e2a7ff70c4","17cee453-31a5-4253-b554-808212d7668a":"fbbd8d85-2fd8-401c-b2a4-4021c6f945cb","6":{"1942ce66-4465-46c4-b504-70eac7d5d928":"23975140-e889-4ec4-9a9b-6678a88e51b3","5493077c-cd4e-4818-8b71-4de2b1901ffb":"b14abefe-c60a-4eca-8f91-315d6d7350ac","7":{"52bf597e-55fb-4d36-8f34-3a501895cbd8":"d80410f7-77ab-42e8-b753-f51d3437c67f","8":{"069247b1-e4cd-4bfa-9026-9407931f5da0":"f5fb04a1-dc37-40b1-8c44-31ccfe4a5974","118b0414-0284-4cf8-9694-a0804df5a58f":"4254a610-2af6-4754-92f9-e6e33e15d84d","9":{"10":{"11":{"0694cb9a-af42-4e8b-8fde-528b320cf3da":"f2b529a7-0ee1-4cb8-b08e-6675bf03799d","12":{"13":{"14":{"15":{"16":{"17":{"18":{"19":{"20":{"21":{"22":{"23":{"24":{"25":{"26":{"27":{"27cb868d-abef-494d-b25a-41a91b7b060a":"68660acd-9c4a-49f2-9a8c-a3397303664b","28":{"29":{"30":{"31":{"54b2e20d-3f31-4b3f-9562-5398c3495d39":"0ad811bb-043f-4290-b51d-3f923a847514","d8bf3642-5a85-40c5-893c-95a57c46e980":"f4c58d35-7b71-4515-9d50-4549c0512f0d"},"45fd7fc1-59ec-4ecb-bc7e-7bb0f3f0a359":"6586542b-3ae0-40cb-8307-d4c4fa50c98c","e1d41477-9706-4c80-a1d1-741321b43412":"1b981488-860e-4a40-9115-d19e22eb70fd"},"c5382984-f16a-4faa-a4ad-58c894818c39":"96878ea3-3c86-42e9-bdde-13b535919135","ca46c9a6-66df-4b3a-90d4-e415aff9a640":"3fd74ef8-1f89-4f73-a10b-42aface558a9"},"fdf7c7d1-e8cb-44b0-8fe1-6139aeb0c31c":"e171cb37-2ce9-4d90-a50f-ec407ce654f5","fe7e896c-13be-43ac-81fa-4783128528e4":"5e426a4f-85aa-4bae-867f-430b2ed9389c"},"5d121af1-44a4-48d2-afd2-dbf3c920b293":"57d7ceed-6ab5-47a6-a9d7-ec00f57ffbff"},"29c88314-e11b-47b2-bdd8-0a7c5594cc84":"57d5b9e9-4313-423f-b867-2195fb42d7a2","5e6c216c-ff0d-4a0a-a7fc-854c2a53f553":"ea1004eb-69f1-481d-b943-edb9dd5df65e"},"70d64923-e5e9-4202-acc5-66446dcb6126":"30453656-420c-4cb6-a6b5-d8179e682b09","88a0db27-c973-46b4-8240-a5a835d46a8d":"2c94d3b5-a0af-4f49-8c87-477b0340d327"},"94838957-b729-44ac-bc65-7cb71ce1aa18":"d78f663e-ae8b-477b-b896-9885a8ed4931","ac500939-4797-4604-85c7-90b06768c490":"7a2ffcf7-95bb-4bdb-ae7e-ca6184f8e70e"},"48961f3f-d390-4972-b7c9-0a99cf6ccab1":"1ad439e8-bcb0-46ba-9654-eb25bd2fdb21","b7d8a082-9cca-4769-9c0b-352e36134fcf":"1e7a5db8-f9c1-46e8-b137-a1a010a4fd46"},"f3b9d634-24bd-422b-92d2-c5712133f29b":"5fc1f970-20dd-462e-ad12-daa6ae02c443","f6e22360-0a78-4b3c-a84e-5077e431eb19":"efadb772-4dca-4f00-b859-bf98f3b481a4"},"d8ddb3b6-c430-4dd0-98db-229b9c3be74d":"a652ecff-c996-4c2b-9c52-d4e3b80c6e83","ee92c073-c3be-45bd-8950-31729f5df6ff":"9111cb19-a112-472b-88a6-36d8ba2aeefa"},"8544bb04-cc8f-4ef1-be32-93d5b02c165b":"7408fc44-c183-4872-95b1-e59baea6aad7","b63b7b35-3984-4c71-9b3c-41364a926ac5":"b4adac24-8040-4c42-87f7-9f65917104e3"},"84ae1c52-e786-43cc-9e35-2890735a6931":"44bdc742-9cb1-4144-b6c3-2ae8a45a8595","f31bde12-4641-4cf6-a601-47cb9c898ba6":"dc831477-97d3-42c2-9ba4-34bf30d252d0"},"bf3a0f5e-461f-4c6e-aadb-43a0c4172cdd":"ddded9d3-880b-4d2b-82c6-411b1b4984d4","bffa86e2-b049-4c1e-8d25-141767b42508":"90ee6767-6559-4c6a-b21f-f271a6d69566"},"78cc9011-a293-4a0b-b3b4-b69eb5598ffe":"22b08c65-bbac-4496-b56a-d9452f1dd237","a9e4a07a-f5ea-4e13-8b3b-52368d058add":"64d88027-6368-4faa-82f4-ab7baeb1fedf"},"d0b216e1-be3b-4ef9-9d36-498c4c7adf00":"1ae67a68-c06f-4ce1-bc15-349945acf9a0","d0e7b646-69dc-45d7-bf38-d3ae725ead0e":"923aa346-9721-49c5-b1c0-ea44a913ef34"},"1c90b757-469f-4b46-ba7e-ba07a5aae8eb":"9c903b75-3126-4854-80e8-bddb8c48fde3","1f22dc78-b8c4-468b-afcd-cf0abfe259ee":"520a94c7-fcee-41cf-974a-84d8904d8372"},"fb25df36-92da-465f-babb-3b8c341e1ee6":"a66c6e07-2ee5-4e0e-bf99-a3721972abe5","ff50c315-ec6c-40f5-b251-724b3ecb1151":"887d1cec-1156-449d-91dd-392919ab5d84"},"2f79b2fd-3fa4-4ac9-8114-a44e70b53c96":"0f4a1077-0708-4fb1-ae7c-99b17446d702","dcda825b-2d14-4001-9faf-fc391b49cda7":"691fc30a-8033-4af4-8a75-094a7b8d8f8e"},"80e5493d-0800-44d2-b4ea-51002c2375a4":"9ed43df0-a2d3-4787-ad83-cac0ab0b4212","a99e1ff4-a58c-4368-bf81-2ba3398be618":"e487ce20-b8a1-4a57-b1e1-122dc1cdb039"},"67830498-f5e6-4786-b711-90b81ba45c65":"f16e0685-9e31-412a-a66b-12b3589ed2fe"},"a5cbb609-f8bf-46e3-a048-2ccd8e033d43":"4bd18392-28b0-4294-8dc9-bc938721fd37","cc9604ff-96fc-4251-8e54-8e7a94d89821":"200d286a-10ce-4b2f-86c4-f5359e17a738"},"7238b2bb-e1d6-4550-9b82-c6175a587cc1":"926d854c-f026-4dcf-8cf4-b9c354142ada","fc213009-cfff-4d0d-9c13-087c0649835a":"3e2b06fe-97a9-4453-b5bf-dfd9e6406a77"}},"de5b5cd5-93aa-40e2-82a7-274e4b077fd6":"8fdefba5-4fb2-425e-989f-8e8eaf1ac266"}}},"c91fac52-7c9d-4b8a-b54c-9286fccb7149":"bfbb37b5-17e9-4701-afd4-94a4a36baa58","d09df540-b8ce-4809-a7db-5a11051ffcf0":"5706ba56-0f00-46f9-a890-698080f69a40"},"d0bca28e-8c18-4b4f-8f4b-5805834f96ea":"6c05b447-0bed-40d5-a5a4-c64b2c964cb3"},"6cb52703-1b35-444b-b458-e0e480198d50":"b90db417-e85a-4a58-874e-14616ac9a3bf","de2a8865-c1f4-4382-8191-44906e182b79":"30bbc061-7dea-4cd4-a570-1389e72bae66"},"b53b8ce2-a2d9-462f-b8ac-e7f1fb585910":"5ba0b61b-58c6-4638-8380-49974cdc875c","bc0802b6-f5fa-4294-b985-80baf508e058":"0f3dd326-bbe4-4172-bd9f-bf89e3685577"},"4a3bda1e-625a-4412-a845-9dd67cc1d67d":"bc19c743-fe7d-4d4e-8841-7af8a7426bad","62898d7e-7c30-458f-b416-03c1a8adda1c":"f4fb85a3-e2d5-42af-a8f9-3bc46395c2ba"}}
 `
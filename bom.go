@@ -0,0 +1,65 @@
+package jsonvalidate
+
+import (
+	"strconv"
+	"strings"
+)
+
+// bom is the UTF-8 encoded byte order mark U+FEFF
+const bom = "\xef\xbb\xbf"
+
+// BOMPolicy selects how a UTF-8 byte order mark (EF BB BF)
+// at the beginning of the input is treated
+type BOMPolicy byte
+
+// BOM policies
+const (
+	// Reject is the default policy. A byte order mark is reported
+	// as ErrUnexpectedBOM unless the dialect is JSON5
+	// which treats it as whitespace
+	Reject BOMPolicy = iota
+
+	// Skip ignores a byte order mark, see RFC 8259 section 8.1
+	Skip
+
+	// Require skips the byte order mark and reports
+	// input not beginning with one as ErrMissingBOM
+	Require
+)
+
+var bomPolicyNames = map[BOMPolicy]string{
+	Reject:  "reject",
+	Skip:    "skip",
+	Require: "require",
+}
+
+// String returns the name of the policy
+func (p BOMPolicy) String() string {
+	if n, ok := bomPolicyNames[p]; ok {
+		return n
+	}
+	return "bom policy (" + strconv.Itoa(int(p)) + ")"
+}
+
+// scanBOM applies Options.BOM to input which is the beginning
+// of the input returning its tail following the byte order mark,
+// if any. Unless final is set done is false if input is too short
+// to tell whether it begins with a byte order mark
+func (sc *scanner) scanBOM(
+	input string,
+	final bool,
+) (s string, code ErrorCode, done bool) {
+	if !final && len(input) < len(bom) && strings.HasPrefix(bom, input) {
+		return input, 0, false
+	}
+	if !strings.HasPrefix(input, bom) {
+		if sc.opts.BOM == Require {
+			return input, ErrMissingBOM, true
+		}
+		return input, 0, true
+	}
+	if sc.opts.BOM == Reject && !sc.rules.json5 {
+		return input, ErrUnexpectedBOM, true
+	}
+	return input[len(bom):], 0, true
+}
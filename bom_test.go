@@ -0,0 +1,94 @@
+package jsonvalidate
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBOM(t *testing.T) {
+	for _, tt := range []struct {
+		name   string
+		in     string
+		opts   Options
+		expect Err
+	}{
+		{"reject_without_bom", `{}`, Options{}, Err{}},
+		{"reject", "\xef\xbb\xbf{}", Options{},
+			Err{DebugCode: ErrUnexpectedBOM, Offset: 0, Line: 1, Column: 1}},
+		{"reject_expect_document", "\xef\xbb\xbf{}", Options{ExpectDocument: true},
+			Err{DebugCode: ErrUnexpectedBOM, Offset: 0, Line: 1, Column: 1}},
+		{"reject_partial", "\xef\xbb{}", Options{},
+			Err{DebugCode: ErrExpectedValue, Offset: 0, Line: 1, Column: 1}},
+		{"reject_not_leading", " \xef\xbb\xbf{}", Options{},
+			Err{DebugCode: ErrExpectedValue, Offset: 1, Line: 1, Column: 2}},
+		{"reject_json5", "\xef\xbb\xbf{a: 1}", Options{Dialect: JSON5}, Err{}},
+		{"skip", "\xef\xbb\xbf{}", Options{BOM: Skip}, Err{}},
+		{"skip_whitespace", "\xef\xbb\xbf \n[1]", Options{BOM: Skip}, Err{}},
+		{"skip_without_bom", `{}`, Options{BOM: Skip}, Err{}},
+		{"skip_json5", "\xef\xbb\xbf{a: 1}", Options{BOM: Skip, Dialect: JSON5}, Err{}},
+		{"skip_twice", "\xef\xbb\xbf\xef\xbb\xbf{}", Options{BOM: Skip},
+			Err{DebugCode: ErrExpectedValue, Offset: 3, Line: 1, Column: 2}},
+		{"skip_error_offset", "\xef\xbb\xbf{\"a\":x}", Options{BOM: Skip},
+			Err{DebugCode: ErrExpectedValue, Offset: 8, Line: 1, Column: 7, Path: "/a"}},
+		{"skip_empty", "\xef\xbb\xbf", Options{BOM: Skip},
+			Err{DebugCode: ErrEmptyInput, Offset: 3, Line: 1, Column: 2}},
+		{"require", "\xef\xbb\xbf{}", Options{BOM: Require}, Err{}},
+		{"require_missing", `{}`, Options{BOM: Require},
+			Err{DebugCode: ErrMissingBOM, Offset: 0, Line: 1, Column: 1}},
+		{"require_partial", "\xef\xbb", Options{BOM: Require},
+			Err{DebugCode: ErrMissingBOM, Offset: 0, Line: 1, Column: 1}},
+		{"require_not_leading", " \xef\xbb\xbf{}", Options{BOM: Require},
+			Err{DebugCode: ErrMissingBOM, Offset: 0, Line: 1, Column: 1}},
+		{"require_empty", "", Options{BOM: Require},
+			Err{DebugCode: ErrMissingBOM, Offset: 0, Line: 1, Column: 1}},
+		{"require_error_offset", "\xef\xbb\xbf\n [1,]", Options{BOM: Require},
+			Err{DebugCode: ErrExpectedValue, Offset: 8, Line: 2, Column: 5, Path: "/1"}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.expect, validateParts(t, tt.opts, tt.in))
+		})
+	}
+}
+
+func TestBOMValueRanges(t *testing.T) {
+	ranges, err := NewParser(0).ValueRanges("\xef\xbb\xbf [1] ", Options{BOM: Skip})
+	require.Zero(t, err)
+	require.Equal(t, []Range{{Start: 4, End: 7}}, ranges)
+}
+
+func TestBOMValidateAll(t *testing.T) {
+	errs := NewParser(0).ValidateAll("\xef\xbb\xbf[1,x,2,y]", Options{BOM: Skip})
+	require.Equal(t, []Err{
+		{DebugCode: ErrExpectedValue, Offset: 6, Line: 1, Column: 5, Path: "/1"},
+		{DebugCode: ErrExpectedValue, Offset: 10, Line: 1, Column: 9, Path: "/3"},
+	}, errs)
+}
+
+func TestBOMNDJSON(t *testing.T) {
+	// Only the first line may begin with a byte order mark
+	in := "\xef\xbb\xbf{}\n{}\n\xef\xbb\xbf{}\n"
+	var errs []error
+	err := NewParser(0).ValidateNDJSON(
+		bytes.NewReader([]byte(in)),
+		Options{BOM: Require},
+		func(line int, err error) bool {
+			errs = append(errs, err)
+			return true
+		},
+	)
+	require.NoError(t, err)
+	require.Equal(t, []error{
+		nil,
+		nil,
+		Err{DebugCode: ErrExpectedValue, Offset: 9, Line: 3, Column: 1},
+	}, errs)
+}
+
+func TestBOMPolicyString(t *testing.T) {
+	require.Equal(t, "reject", Reject.String())
+	require.Equal(t, "skip", Skip.String())
+	require.Equal(t, "require", Require.String())
+	require.Equal(t, "bom policy (7)", BOMPolicy(7).String())
+}
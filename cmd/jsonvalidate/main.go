@@ -0,0 +1,308 @@
+// Command jsonvalidate validates JSON read from files or standard input
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	jsonvalidate "github.com/romshark/jsonvalidate-go"
+)
+
+func main() {
+	expectDocument := flag.Bool(
+		"document", false,
+		"expect the input to be a JSON object",
+	)
+	allowDuplicateKeys := flag.Bool(
+		"allow-duplicate-keys", false,
+		"accept objects with duplicate keys",
+	)
+	maxDepth := flag.Int(
+		"max-depth", 0,
+		"maximum nesting depth of arrays and objects (0 means no limit)",
+	)
+	maxInputBytes := flag.Int(
+		"max-input-bytes", 0,
+		"maximum length of the input in bytes (0 means no limit)",
+	)
+	maxStringBytes := flag.Int(
+		"max-string-bytes", 0,
+		"maximum length of string values in bytes (0 means no limit)",
+	)
+	maxKeyBytes := flag.Int(
+		"max-key-bytes", 0,
+		"maximum length of object keys in bytes (0 means no limit)",
+	)
+	maxNumberDigits := flag.Int(
+		"max-number-digits", 0,
+		"maximum number of digits of numbers (0 means no limit)",
+	)
+	maxObjectKeys := flag.Int(
+		"max-object-keys", 0,
+		"maximum number of fields of an object (0 means no limit)",
+	)
+	maxArrayElements := flag.Int(
+		"max-array-elements", 0,
+		"maximum number of elements of an array (0 means no limit)",
+	)
+	maxTotalValues := flag.Int(
+		"max-total-values", 0,
+		"maximum number of values in the input (0 means no limit)",
+	)
+	validateUTF8 := flag.Bool(
+		"validate-utf8", false,
+		"reject keys and strings that aren't valid UTF-8",
+	)
+	allowControlChars := flag.Bool(
+		"allow-control-chars", false,
+		"accept unescaped control characters in string values",
+	)
+	validateSurrogates := flag.Bool(
+		"validate-surrogates", false,
+		"reject \\u escape sequences encoding unpaired surrogates",
+	)
+	profileName := flag.String(
+		"profile", jsonvalidate.Legacy.String(),
+		"conformance profile: legacy, rfc8259, ecma404 or ijson",
+	)
+	dialectName := flag.String(
+		"dialect", jsonvalidate.JSON.String(),
+		"input dialect: json or json5",
+	)
+	bomName := flag.String(
+		"bom", jsonvalidate.Reject.String(),
+		"byte order mark policy: reject, skip or require",
+	)
+	allowTrailingCommas := flag.Bool(
+		"allow-trailing-commas", false,
+		"accept a ',' following the last field or element",
+	)
+	allowComments := flag.Bool(
+		"allow-comments", false,
+		"accept // and /* */ comments",
+	)
+	allowNonFinite := flag.Bool(
+		"allow-non-finite", false,
+		"accept the literals NaN, Infinity and -Infinity",
+	)
+	multipleValues := flag.Bool(
+		"multiple-values", false,
+		"accept several top-level values in a row",
+	)
+	maxErrors := flag.Int(
+		"max-errors", 1,
+		"maximum number of errors reported per file (0 means no limit), "+
+			"reporting more than one error reads the whole file into memory",
+	)
+	ndjson := flag.Bool(
+		"ndjson", false,
+		"validate newline-delimited JSON with one value per line",
+	)
+	jsonSeq := flag.Bool(
+		"json-seq", false,
+		"validate a JSON text sequence (RFC 7464) with one value per record",
+	)
+	skipBlankLines := flag.Bool(
+		"skip-blank-lines", false,
+		"skip blank lines of newline-delimited JSON",
+	)
+	flag.Usage = func() {
+		fmt.Fprintf(
+			flag.CommandLine.Output(),
+			"Usage: %s [flags] [file ...]\n\n"+
+				"Reads from standard input if no file is given.\n\n",
+			os.Args[0],
+		)
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if *ndjson && *jsonSeq {
+		fmt.Fprintln(os.Stderr, "-ndjson and -json-seq are mutually exclusive")
+		os.Exit(2)
+	}
+	format := formatJSON
+	switch {
+	case *ndjson:
+		format = formatNDJSON
+	case *jsonSeq:
+		format = formatJSONSeq
+	}
+
+	profile, ok := parseProfile(*profileName)
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown profile: %q\n", *profileName)
+		os.Exit(2)
+	}
+	dialect, ok := parseDialect(*dialectName)
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown dialect: %q\n", *dialectName)
+		os.Exit(2)
+	}
+	bomPolicy, ok := parseBOMPolicy(*bomName)
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown bom policy: %q\n", *bomName)
+		os.Exit(2)
+	}
+
+	opts := jsonvalidate.Options{
+		ExpectDocument:        *expectDocument,
+		AllowDuplicateKeys:    *allowDuplicateKeys,
+		MaxDepth:              *maxDepth,
+		MaxInputBytes:         *maxInputBytes,
+		MaxStringBytes:        *maxStringBytes,
+		MaxKeyBytes:           *maxKeyBytes,
+		MaxNumberDigits:       *maxNumberDigits,
+		MaxObjectKeys:         *maxObjectKeys,
+		MaxArrayElements:      *maxArrayElements,
+		MaxTotalValues:        *maxTotalValues,
+		ValidateUTF8:          *validateUTF8,
+		AllowControlChars:     *allowControlChars,
+		ValidateSurrogates:    *validateSurrogates,
+		Profile:               profile,
+		Dialect:               dialect,
+		BOM:                   bomPolicy,
+		AllowTrailingCommas:   *allowTrailingCommas,
+		AllowComments:         *allowComments,
+		AllowMultipleValues:   *multipleValues,
+		AllowNonFiniteNumbers: *allowNonFinite,
+		MaxErrors:             *maxErrors,
+		SkipBlankLines:        *skipBlankLines,
+	}
+	parser := jsonvalidate.NewParser(0)
+
+	files := flag.Args()
+	if len(files) < 1 {
+		files = []string{"-"}
+	}
+
+	failed := false
+	for _, name := range files {
+		errs := validateFile(parser, name, opts, format)
+		if name == "-" {
+			name = "<stdin>"
+		}
+		for _, err := range errs {
+			fmt.Fprintf(os.Stderr, "%s: %s\n", name, err)
+			failed = true
+		}
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
+
+// Input formats
+const (
+	formatJSON = iota
+	formatNDJSON
+	formatJSONSeq
+)
+
+// validateFile validates the file with the given name and format,
+// "-" stands for standard input
+func validateFile(
+	parser *jsonvalidate.Parser,
+	name string,
+	opts jsonvalidate.Options,
+	format int,
+) []error {
+	f := os.Stdin
+	if name != "-" {
+		var err error
+		if f, err = os.Open(name); err != nil {
+			return []error{err}
+		}
+		defer f.Close()
+	}
+
+	var errs []error
+	// collect collects the errors of lines and records
+	collect := func(err error) bool {
+		if err != nil {
+			errs = append(errs, err)
+		}
+		return opts.MaxErrors < 1 || len(errs) < opts.MaxErrors
+	}
+	switch format {
+	case formatNDJSON:
+		// Each line is validated separately
+		err := parser.ValidateNDJSON(f, opts, func(line int, err error) bool {
+			return collect(err)
+		})
+		if err != nil {
+			errs = append(errs, err)
+		}
+		return errs
+	case formatJSONSeq:
+		// Each record is validated separately
+		err := parser.ValidateJSONSeq(f, opts, func(rec jsonvalidate.SeqRecord) bool {
+			return collect(rec.Err)
+		})
+		if err != nil {
+			errs = append(errs, err)
+		}
+		return errs
+	}
+
+	if opts.MaxErrors == 1 {
+		if err := parser.ValidateReader(f, opts); err != nil {
+			return []error{err}
+		}
+		return nil
+	}
+
+	// Recovering from errors requires the whole input
+	input, err := ioutil.ReadAll(f)
+	if err != nil {
+		return []error{err}
+	}
+	for _, err := range parser.ValidateAllBytes(input, opts) {
+		errs = append(errs, err)
+	}
+	return errs
+}
+
+// parseProfile returns the profile with the given name
+func parseProfile(name string) (jsonvalidate.Profile, bool) {
+	for _, p := range []jsonvalidate.Profile{
+		jsonvalidate.Legacy,
+		jsonvalidate.RFC8259,
+		jsonvalidate.ECMA404,
+		jsonvalidate.IJSON,
+	} {
+		if p.String() == name {
+			return p, true
+		}
+	}
+	return 0, false
+}
+
+// parseDialect returns the dialect with the given name
+func parseDialect(name string) (jsonvalidate.Dialect, bool) {
+	for _, d := range []jsonvalidate.Dialect{
+		jsonvalidate.JSON,
+		jsonvalidate.JSON5,
+	} {
+		if d.String() == name {
+			return d, true
+		}
+	}
+	return 0, false
+}
+
+// parseBOMPolicy returns the byte order mark policy with the given name
+func parseBOMPolicy(name string) (jsonvalidate.BOMPolicy, bool) {
+	for _, p := range []jsonvalidate.BOMPolicy{
+		jsonvalidate.Reject,
+		jsonvalidate.Skip,
+		jsonvalidate.Require,
+	} {
+		if p.String() == name {
+			return p, true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,113 @@
+package jsonvalidate
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAllowTrailingCommas(t *testing.T) {
+	opts := Options{AllowTrailingCommas: true}
+	for _, tt := range []struct {
+		name     string
+		in       string
+		comments bool
+	}{
+		{"array", `[1,2,]`, false},
+		{"array_single", `[1,]`, false},
+		{"array_whitespace", "[1 , \n]", false},
+		{"object", `{"a":1,}`, false},
+		{"object_whitespace", "{\"a\":1 ,\n}", false},
+		{"nested", `{"a":[{"b":[],},[1,],],"c":{},}`, false},
+		{"with_comments", "[1, /* 2 */]", true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			o := opts
+			o.AllowComments = tt.comments
+			require.Zero(t, validateParts(t, o, tt.in))
+			require.NotZero(t, NewParser(0).Validate(tt.in, Options{}).DebugCode)
+		})
+	}
+}
+
+func TestAllowTrailingCommasInvalid(t *testing.T) {
+	opts := Options{AllowTrailingCommas: true}
+	for _, tt := range []struct {
+		name   string
+		in     string
+		expect Err
+	}{
+		{"array_leading_comma", `[,]`,
+			Err{DebugCode: ErrExpectedValue, Offset: 1, Line: 1, Column: 2, Path: "/0"}},
+		{"array_leading_comma_element", `[,1]`,
+			Err{DebugCode: ErrExpectedValue, Offset: 1, Line: 1, Column: 2, Path: "/0"}},
+		{"array_double_comma", `[1,,]`,
+			Err{DebugCode: ErrExpectedValue, Offset: 3, Line: 1, Column: 4, Path: "/1"}},
+		{"array_double_comma_element", `[1,,2]`,
+			Err{DebugCode: ErrExpectedValue, Offset: 3, Line: 1, Column: 4, Path: "/1"}},
+		{"array_unterminated", `[1,`,
+			Err{DebugCode: ErrUnexpectedEOF, Offset: 3, Line: 1, Column: 4, Path: "/1"}},
+		{"object_leading_comma", `{,}`,
+			Err{DebugCode: ErrExpectedKey, Offset: 1, Line: 1, Column: 2}},
+		{"object_double_comma", `{"a":1,,}`,
+			Err{DebugCode: ErrExpectedKey, Offset: 7, Line: 1, Column: 8}},
+		{"object_comma_after_key", `{"a",}`,
+			Err{DebugCode: ErrExpectedColon, Offset: 4, Line: 1, Column: 5, Path: "/a"}},
+		{"object_comma_after_colon", `{"a":,}`,
+			Err{DebugCode: ErrExpectedValue, Offset: 5, Line: 1, Column: 6, Path: "/a"}},
+		{"mismatched_bracket", `[1,}`,
+			Err{DebugCode: ErrExpectedValue, Offset: 3, Line: 1, Column: 4, Path: "/1"}},
+		{"mismatched_brace", `{"a":1,]`,
+			Err{DebugCode: ErrExpectedKey, Offset: 7, Line: 1, Column: 8}},
+		{"top_level", `1,`,
+			Err{DebugCode: ErrTrailingData, Offset: 1, Line: 1, Column: 2}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.expect, validateParts(t, opts, tt.in))
+		})
+	}
+}
+
+func TestAllowTrailingCommasMaxArrayElements(t *testing.T) {
+	for _, tt := range []struct {
+		name   string
+		in     string
+		opts   Options
+		expect Err
+	}{
+		{"trailing_comma", `[1,2,]`, Options{}, Err{}},
+		{"trailing_comma_whitespace", "[1,2, \n]", Options{}, Err{}},
+		{"trailing_comma_comment", "[1,2, /* 3 */ ]", Options{AllowComments: true}, Err{}},
+		{"trailing_comma_json5", "[1,2,\xc2\xa0]", Options{Dialect: JSON5}, Err{}},
+		// Reported at the ',' like without AllowTrailingCommas
+		{"exceeded", `[1,2,3]`, Options{},
+			Err{DebugCode: ErrMaxArrayElements, Offset: 4, Line: 1, Column: 5, Path: "/2"}},
+		{"exceeded_whitespace", "[1,2, \n3]", Options{},
+			Err{DebugCode: ErrMaxArrayElements, Offset: 4, Line: 1, Column: 5, Path: "/2"}},
+		{"exceeded_comment", "[1,2, /* ] */ 3]", Options{AllowComments: true},
+			Err{DebugCode: ErrMaxArrayElements, Offset: 4, Line: 1, Column: 5, Path: "/2"}},
+		{"exceeded_eof", `[1,2, `, Options{},
+			Err{DebugCode: ErrMaxArrayElements, Offset: 4, Line: 1, Column: 5, Path: "/2"}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := tt.opts
+			opts.AllowTrailingCommas = true
+			opts.MaxArrayElements = 2
+			require.Equal(t, tt.expect, validateParts(t, opts, tt.in))
+		})
+	}
+
+	// Without AllowTrailingCommas
+	require.Equal(t,
+		Err{DebugCode: ErrMaxArrayElements, Offset: 4, Line: 1, Column: 5, Path: "/2"},
+		NewParser(0).Validate(`[1,2,3]`, Options{MaxArrayElements: 2}),
+	)
+}
+
+func TestAllowTrailingCommasProfiles(t *testing.T) {
+	for _, p := range []Profile{RFC8259, ECMA404, IJSON} {
+		testProfile(t, Options{Profile: p, AllowTrailingCommas: true}, []profileCase{
+			{p.String(), `[1,]`, ErrExpectedValue, 3},
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package jsonvalidate
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAllowComments(t *testing.T) {
+	opts := Options{AllowComments: true}
+	for _, tt := range []struct {
+		name string
+		in   string
+	}{
+		{"line", "// comment\n{\"a\": 1}"},
+		{"line_at_end", "[1, 2] // comment"},
+		{"line_crlf", "[1, // one\r\n2]"},
+		{"block", "/* comment */ true"},
+		{"block_multiline", "{\n/*\n * a\n */\n\"a\": /* b */ 1}"},
+		{"block_stars", "/***/1/** * */"},
+		{"adjacent", "/*a*//*b*/// c\n//d\nnull"},
+		{"everywhere", "/*0*/{/*1*/\"a\"/*2*/:/*3*/[/*4*/1/*5*/,/*6*/2/*7*/]/*8*/}/*9*/"},
+		{"brackets_in_comment", "[1 /* ] } */, 2] // ]"},
+		{"slash_in_string", `["//", "/*"] /* "x" */`},
+		{"number_followed_by_comment", "1/* a */"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Zero(t, validateParts(t, opts, tt.in))
+			require.NotZero(t, NewParser(0).Validate(tt.in, Options{}).DebugCode)
+		})
+	}
+}
+
+func TestAllowCommentsInvalid(t *testing.T) {
+	opts := Options{AllowComments: true}
+	for _, tt := range []struct {
+		name   string
+		in     string
+		expect Err
+	}{
+		{"unterminated_block", "[1, /* 2]",
+			Err{DebugCode: ErrUnterminatedComment, Offset: 4, Line: 1, Column: 5, Path: "/1"}},
+		{"unterminated_block_star", "1 /*/",
+			Err{DebugCode: ErrUnterminatedComment, Offset: 2, Line: 1, Column: 3}},
+		{"unterminated_block_multiline", "{\"a\": 1}\n/*\n",
+			Err{DebugCode: ErrUnterminatedComment, Offset: 9, Line: 2, Column: 1}},
+		{"single_slash", "[1, /]",
+			Err{DebugCode: ErrExpectedValue, Offset: 4, Line: 1, Column: 5, Path: "/1"}},
+		{"trailing_slash", "1 /",
+			Err{DebugCode: ErrTrailingData, Offset: 2, Line: 1, Column: 3}},
+		{"only_comments", "/* a */ // b",
+			Err{DebugCode: ErrEmptyInput, Offset: 12, Line: 1, Column: 13}},
+		{"comment_in_literal", "tr/**/ue",
+			Err{DebugCode: ErrInvalidTrue, Offset: 0, Line: 1, Column: 1}},
+		{"line_comment_hides_bracket", "[1 // ]",
+			Err{DebugCode: ErrUnexpectedEOF, Offset: 7, Line: 1, Column: 8, Path: "/0"}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.expect, validateParts(t, opts, tt.in))
+		})
+	}
+}
+
+func TestAllowCommentsProfiles(t *testing.T) {
+	for _, p := range []Profile{RFC8259, ECMA404, IJSON} {
+		testProfile(t, Options{Profile: p, AllowComments: true}, []profileCase{
+			{p.String(), "[1 /* c */]", ErrExpectedCommaOrBracket, 3},
+		})
+	}
+}
+
+func TestAllowCommentsValidateAll(t *testing.T) {
+	opts := Options{AllowComments: true}
+	errs := NewParser(0).ValidateAll("[x /* ] */, 1, y]", opts)
+	require.Equal(t, []Err{
+		{DebugCode: ErrExpectedValue, Offset: 1, Line: 1, Column: 2, Path: "/0"},
+		{DebugCode: ErrExpectedValue, Offset: 15, Line: 1, Column: 16, Path: "/2"},
+	}, errs)
+}
@@ -0,0 +1,268 @@
+package jsonvalidate
+
+import "strconv"
+
+// ErrorCode identifies the kind of a validation error.
+// ErrorCode implements the error interface, the codes are sentinel errors
+// an Err can be matched against using errors.Is
+type ErrorCode int
+
+// Error codes. The codes of earlier versions that are no longer
+// reported (7, 13, 14, 50, 500, 601, 700, 701, 704, 706, 707 and 800)
+// are retired and never reused
+const (
+	// ErrExpectedObject is returned when Options.ExpectDocument
+	// is set but the top-level value is not an object
+	ErrExpectedObject ErrorCode = 1
+
+	// ErrExpectedColon is returned when an object key
+	// isn't followed by ':'
+	ErrExpectedColon ErrorCode = 5
+
+	// ErrUnexpectedEOF is returned when the input ends
+	// before the top-level value is complete
+	ErrUnexpectedEOF ErrorCode = 8
+
+	// ErrExpectedCommaOrBracket is returned when an array element
+	// is followed by neither ',' nor ']'
+	ErrExpectedCommaOrBracket ErrorCode = 15
+
+	// ErrExpectedCommaOrBrace is returned when an object field
+	// is followed by neither ',' nor '}'
+	ErrExpectedCommaOrBrace ErrorCode = 16
+
+	// ErrExpectedValue is returned when a value is expected
+	// but the character can't begin one
+	ErrExpectedValue ErrorCode = 20
+
+	// ErrExpectedKey is returned when an object key is expected
+	// but the character can't begin one
+	ErrExpectedKey ErrorCode = 21
+
+	// ErrInvalidTrue is returned for a malformed true literal
+	ErrInvalidTrue ErrorCode = 22
+
+	// ErrInvalidFalse is returned for a malformed false literal
+	ErrInvalidFalse ErrorCode = 23
+
+	// ErrInvalidNull is returned for a malformed null literal
+	ErrInvalidNull ErrorCode = 24
+
+	// ErrInvalidInfinity is returned for a malformed Infinity literal
+	ErrInvalidInfinity ErrorCode = 25
+
+	// ErrInvalidNaN is returned for a malformed NaN literal
+	ErrInvalidNaN ErrorCode = 26
+
+	// ErrControlCharInKey is returned when an object key
+	// contains a control character (U+0000 through U+001F)
+	ErrControlCharInKey ErrorCode = 29
+
+	// ErrControlCharInString is returned when a string value
+	// contains an unescaped control character (U+0000 through U+001F)
+	// unless Options.AllowControlChars is set
+	ErrControlCharInString ErrorCode = 30
+
+	// ErrTrailingData is returned when the top-level value
+	// is followed by anything but whitespace
+	ErrTrailingData ErrorCode = 61
+
+	// ErrEmptyInput is returned when the input contains no value
+	ErrEmptyInput ErrorCode = 67
+
+	// ErrEmptyKey is returned for the empty object key ""
+	// by the Legacy profile
+	ErrEmptyKey ErrorCode = 78
+
+	// ErrDuplicateKey is returned when an object contains the same key
+	// more than once, unless Options.AllowDuplicateKeys is set
+	ErrDuplicateKey ErrorCode = 91
+
+	// ErrMissingRecordSeparator is returned by ValidateJSONSeq
+	// when a JSON text sequence doesn't begin with RS
+	ErrMissingRecordSeparator ErrorCode = 92
+
+	// ErrTruncatedText is returned by ValidateJSONSeq for texts of
+	// a JSON text sequence that were cut off, see RFC 7464 section 2.3
+	ErrTruncatedText ErrorCode = 93
+
+	// ErrUnterminatedComment is returned for a block comment
+	// missing the closing */ when Options.AllowComments is set
+	ErrUnterminatedComment ErrorCode = 94
+
+	// ErrHandlerAborted is returned when the Handler
+	// returns an error, see Err.Cause
+	ErrHandlerAborted ErrorCode = 95
+
+	// ErrShortUnicodeEscape is returned when a \u escape sequence
+	// has less than 4 hexadecimal digits
+	ErrShortUnicodeEscape ErrorCode = 400
+
+	// ErrInvalidUnicodeEscape is returned when a \u escape sequence
+	// contains a character that's not a hexadecimal digit
+	ErrInvalidUnicodeEscape ErrorCode = 401
+
+	// ErrInvalidEscape is returned for an unknown escape sequence
+	ErrInvalidEscape ErrorCode = 402
+
+	// ErrLoneHighSurrogate is returned when Options.ValidateSurrogates
+	// is set and a \u escape sequence encoding a high surrogate
+	// (U+D800 through U+DBFF) isn't followed by one encoding a low surrogate
+	ErrLoneHighSurrogate ErrorCode = 403
+
+	// ErrLoneLowSurrogate is returned when Options.ValidateSurrogates
+	// is set and a \u escape sequence encoding a low surrogate
+	// (U+DC00 through U+DFFF) isn't preceded by one encoding a high surrogate
+	ErrLoneLowSurrogate ErrorCode = 404
+
+	// ErrInvalidHexEscape is returned when a JSON5 \x escape sequence
+	// isn't followed by 2 hexadecimal digits
+	ErrInvalidHexEscape ErrorCode = 405
+
+	// ErrInvalidIdentifier is returned when a \u escape sequence
+	// of a JSON5 identifier key doesn't encode a character
+	// that's allowed at its position in the identifier
+	ErrInvalidIdentifier ErrorCode = 406
+
+	// ErrUnexpectedBOM is returned when the input begins
+	// with a byte order mark and Options.BOM is Reject
+	ErrUnexpectedBOM ErrorCode = 501
+
+	// ErrMissingBOM is returned when the input doesn't begin
+	// with a byte order mark and Options.BOM is Require
+	ErrMissingBOM ErrorCode = 502
+
+	// ErrInvalidUTF8 is returned when Options.ValidateUTF8 is set
+	// and a key or string value isn't valid UTF-8
+	ErrInvalidUTF8 ErrorCode = 503
+
+	// ErrNoncharacter is returned by the IJSON profile when a key
+	// or string value contains a noncharacter (U+FDD0 through U+FDEF
+	// and the last two code points of each plane such as U+FFFE),
+	// literally or encoded by \u escape sequences
+	ErrNoncharacter ErrorCode = 504
+
+	// ErrUnterminatedString is returned when a string
+	// or an object key is missing the closing quote
+	ErrUnterminatedString ErrorCode = 600
+
+	// ErrExpectedDigit is returned when a number
+	// doesn't begin with a digit after the optional minus sign
+	ErrExpectedDigit ErrorCode = 702
+
+	// ErrLeadingZero is returned when the integer part of a number
+	// has more than one digit and begins with 0
+	ErrLeadingZero ErrorCode = 703
+
+	// ErrExpectedFractionDigit is returned when
+	// the decimal point of a number isn't followed by a digit
+	ErrExpectedFractionDigit ErrorCode = 705
+
+	// ErrExpectedExponentDigit is returned when
+	// the exponent of a number has no digits
+	ErrExpectedExponentDigit ErrorCode = 708
+
+	// ErrNumberOutOfRange is returned by the IJSON profile
+	// for numbers exceeding the magnitude or precision
+	// of IEEE 754 double precision numbers
+	ErrNumberOutOfRange ErrorCode = 710
+
+	// ErrExpectedHexDigit is returned when a JSON5 hexadecimal number
+	// has no digits following 0x
+	ErrExpectedHexDigit ErrorCode = 711
+
+	// ErrMaxDepth is returned when an array or object
+	// would exceed Options.MaxDepth
+	ErrMaxDepth ErrorCode = 900
+
+	// ErrMaxInputBytes is returned when the input
+	// is longer than Options.MaxInputBytes
+	ErrMaxInputBytes ErrorCode = 901
+
+	// ErrMaxStringBytes is returned when a string value
+	// is longer than Options.MaxStringBytes
+	ErrMaxStringBytes ErrorCode = 902
+
+	// ErrMaxKeyBytes is returned when an object key
+	// is longer than Options.MaxKeyBytes
+	ErrMaxKeyBytes ErrorCode = 903
+
+	// ErrMaxNumberDigits is returned when a number
+	// has more digits than Options.MaxNumberDigits
+	ErrMaxNumberDigits ErrorCode = 904
+
+	// ErrMaxObjectKeys is returned when an object
+	// has more fields than Options.MaxObjectKeys
+	ErrMaxObjectKeys ErrorCode = 905
+
+	// ErrMaxArrayElements is returned when an array
+	// has more elements than Options.MaxArrayElements.
+	// It's reported at the ',' preceding the element beyond the limit
+	ErrMaxArrayElements ErrorCode = 906
+
+	// ErrMaxTotalValues is returned when the input
+	// contains more values than Options.MaxTotalValues
+	ErrMaxTotalValues ErrorCode = 907
+)
+
+var errorCodeMessages = map[ErrorCode]string{
+	ErrExpectedObject:         "expected object",
+	ErrExpectedColon:          "expected ':' after object key",
+	ErrUnexpectedEOF:          "unexpected end of input",
+	ErrExpectedCommaOrBracket: "expected ',' or ']' after array element",
+	ErrExpectedCommaOrBrace:   "expected ',' or '}' after object field",
+	ErrExpectedValue:          "expected value",
+	ErrExpectedKey:            "expected object key",
+	ErrInvalidTrue:            "invalid literal, expected true",
+	ErrInvalidFalse:           "invalid literal, expected false",
+	ErrInvalidNull:            "invalid literal, expected null",
+	ErrInvalidInfinity:        "invalid literal, expected Infinity",
+	ErrInvalidNaN:             "invalid literal, expected NaN",
+	ErrControlCharInKey:       "control character in object key",
+	ErrControlCharInString:    "control character in string",
+	ErrTrailingData:           "unexpected data after top-level value",
+	ErrEmptyInput:             "empty input",
+	ErrEmptyKey:               "empty object key",
+	ErrDuplicateKey:           "duplicate object key",
+	ErrMissingRecordSeparator: "missing record separator",
+	ErrTruncatedText:          "truncated JSON text",
+	ErrUnterminatedComment:    "unterminated block comment",
+	ErrHandlerAborted:         "aborted by handler",
+	ErrShortUnicodeEscape:     "incomplete \\u escape sequence",
+	ErrInvalidUnicodeEscape:   "invalid hex digit in \\u escape sequence",
+	ErrInvalidEscape:          "invalid escape sequence",
+	ErrLoneHighSurrogate:      "high surrogate not followed by low surrogate",
+	ErrLoneLowSurrogate:       "low surrogate not preceded by high surrogate",
+	ErrInvalidHexEscape:       "invalid \\x escape sequence",
+	ErrInvalidIdentifier:      "invalid \\u escape sequence in identifier",
+	ErrInvalidUTF8:            "invalid UTF-8 in string",
+	ErrNoncharacter:           "noncharacter in string",
+	ErrUnexpectedBOM:          "unexpected byte order mark",
+	ErrMissingBOM:             "missing byte order mark",
+	ErrUnterminatedString:     "unterminated string",
+	ErrExpectedDigit:          "expected digit in number",
+	ErrLeadingZero:            "leading zero in number",
+	ErrExpectedFractionDigit:  "expected digit in fraction part of number",
+	ErrExpectedExponentDigit:  "expected digit in exponent part of number",
+	ErrNumberOutOfRange:       "number out of range",
+	ErrExpectedHexDigit:       "expected hexadecimal digit",
+	ErrMaxDepth:               "maximum nesting depth exceeded",
+	ErrMaxInputBytes:          "maximum input length exceeded",
+	ErrMaxStringBytes:         "maximum string length exceeded",
+	ErrMaxKeyBytes:            "maximum object key length exceeded",
+	ErrMaxNumberDigits:        "maximum number of digits exceeded",
+	ErrMaxObjectKeys:          "maximum number of object keys exceeded",
+	ErrMaxArrayElements:       "maximum number of array elements exceeded",
+	ErrMaxTotalValues:         "maximum number of values exceeded",
+}
+
+// String returns the description of the error code
+func (c ErrorCode) String() string {
+	if m, ok := errorCodeMessages[c]; ok {
+		return m
+	}
+	return "error (" + strconv.Itoa(int(c)) + ")"
+}
+
+// Error implements the error interface
+func (c ErrorCode) Error() string { return c.String() }
@@ -0,0 +1,63 @@
+package jsonvalidate
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestErrorCodeString(t *testing.T) {
+	messages := map[string]ErrorCode{}
+	for code := range errorCodeMessages {
+		m := code.String()
+		require.NotEmpty(t, m)
+		require.NotContains(t, messages, m, "message of %d reused", code)
+		messages[m] = code
+	}
+	require.Equal(t, "error (12345)", ErrorCode(12345).String())
+}
+
+func TestErrorCodeRetired(t *testing.T) {
+	// Codes of earlier versions must not be given another meaning
+	for _, code := range []ErrorCode{
+		7, 13, 14, 50, 500, 601, 700, 701, 704, 706, 707, 800,
+	} {
+		require.NotContains(t, errorCodeMessages, code)
+	}
+}
+
+func TestErrError(t *testing.T) {
+	err := NewParser(0).Validate(`{"x": 1, "x": 2}`, Options{})
+	require.Equal(t,
+		"duplicate object key at /x, line 1, column 10 (offset 9)",
+		err.Error(),
+	)
+	require.Equal(t,
+		"duplicate object key at offset 9",
+		Err{DebugCode: ErrDuplicateKey, Offset: 9}.Error(),
+	)
+}
+
+func TestErrIs(t *testing.T) {
+	var err error = NewParser(0).Validate(`[1,2`, Options{})
+	require.True(t, errors.Is(err, ErrUnexpectedEOF))
+	require.False(t, errors.Is(err, ErrDuplicateKey))
+
+	var code ErrorCode
+	require.True(t, errors.As(err, &code))
+	require.Equal(t, ErrUnexpectedEOF, code)
+}
+
+func TestCheck(t *testing.T) {
+	parser := NewParser(0)
+	require.NoError(t, parser.Check(`{"x":1}`, Options{}))
+	require.NoError(t, parser.CheckBytes([]byte(`{"x":1}`), Options{}))
+
+	err := parser.Check(`{"x":}`, Options{})
+	require.Equal(t, Err{DebugCode: ErrExpectedValue, Offset: 5, Line: 1, Column: 6, Path: "/x"}, err)
+	require.True(t, errors.Is(err, ErrExpectedValue))
+
+	err = parser.CheckBytes([]byte(`{"x":}`), Options{})
+	require.Equal(t, Err{DebugCode: ErrExpectedValue, Offset: 5, Line: 1, Column: 6, Path: "/x"}, err)
+}
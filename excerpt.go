@@ -0,0 +1,101 @@
+package jsonvalidate
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// excerptEllipsis marks the parts of the line left out of an excerpt
+const excerptEllipsis = "..."
+
+// Excerpt returns the line of the input the error was found in followed by
+// a line with a caret marking the offending byte. Lines longer than
+// contextBytes on either side of the error are truncated, zero means
+// no truncation. Non-printable characters and bytes that aren't valid
+// UTF-8 are escaped. Excerpt returns an empty string if the error
+// isn't located in the given input
+func (err Err) Excerpt(input string, contextBytes int) string {
+	if err.Offset < 0 || err.Offset > len(input) {
+		return ""
+	}
+
+	// Find the line
+	start := strings.LastIndexByte(input[:err.Offset], '\n') + 1
+	end := len(input)
+	if i := strings.IndexByte(input[err.Offset:], '\n'); i >= 0 {
+		end = err.Offset + i
+		if end > start && input[end-1] == '\r' && end-1 != err.Offset {
+			// Leave out the CR of CRLF unless it's the offending byte
+			end--
+		}
+	}
+
+	// Truncate the line around the error
+	// without splitting UTF-8 sequences
+	var prefix, suffix string
+	if contextBytes > 0 {
+		if err.Offset-start > contextBytes {
+			start = err.Offset - contextBytes
+			for start < err.Offset && !utf8.RuneStart(input[start]) {
+				start++
+			}
+			prefix = excerptEllipsis
+		}
+		if end-err.Offset > contextBytes+1 {
+			// Include the offending byte
+			end = err.Offset + 1 + contextBytes
+			for end < len(input) && !utf8.RuneStart(input[end]) {
+				end++
+			}
+			suffix = excerptEllipsis
+		}
+	}
+	if err.Offset > end {
+		// The error is located at the line terminator
+		end = err.Offset
+	}
+
+	var b strings.Builder
+	b.WriteString(prefix)
+	column := len(prefix)
+	for i := start; i < end; {
+		if i == err.Offset {
+			column = b.Len()
+		}
+		i += writeExcerptRune(&b, input[i:end])
+	}
+	if err.Offset == end {
+		column = b.Len()
+	}
+	b.WriteString(suffix)
+
+	// Mark the error, each rune of the line takes one column
+	line := b.String()
+	b.WriteByte('\n')
+	b.WriteString(strings.Repeat(" ", utf8.RuneCountInString(line[:column])))
+	b.WriteByte('^')
+	return b.String()
+}
+
+// writeExcerptRune writes the first rune of s escaping it if necessary
+// and returns its length in bytes
+func writeExcerptRune(b *strings.Builder, s string) int {
+	r, size := utf8.DecodeRuneInString(s)
+	switch {
+	case size == 1 && (r == utf8.RuneError || !unicode.IsPrint(r)):
+		// ASCII control character or invalid UTF-8
+		const digits = "0123456789abcdef"
+		b.WriteString(`\x`)
+		b.WriteByte(digits[s[0]>>4])
+		b.WriteByte(digits[s[0]&0x0f])
+	case unicode.IsPrint(r):
+		b.WriteString(s[:size])
+	default:
+		// Other non-printable runes such as U+2028 are escaped as \u2028
+		q := strconv.QuoteRuneToASCII(r)
+		b.WriteString(q[1 : len(q)-1])
+	}
+	return size
+}
@@ -0,0 +1,99 @@
+package jsonvalidate
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestErrExcerpt(t *testing.T) {
+	for _, tt := range []struct {
+		name         string
+		in           string
+		contextBytes int
+		expect       string
+	}{
+		{
+			"single line",
+			`{"a": [1, 2,, 3]}`, 0,
+			`{"a": [1, 2,, 3]}` + "\n" +
+				`            ^`,
+		}, {
+			"multiple lines",
+			"{\n  \"a\": 1,\n  \"b\": x\n}", 0,
+			`  "b": x` + "\n" +
+				`       ^`,
+		}, {
+			"CRLF",
+			"{\r\n  \"a\": x\r\n}", 0,
+			`  "a": x` + "\n" +
+				`       ^`,
+		}, {
+			"end of input",
+			`{"a": [1, 2`, 0,
+			`{"a": [1, 2` + "\n" +
+				`           ^`,
+		}, {
+			"end of input_after newline",
+			"[1,\n", 0,
+			"\n^",
+		}, {
+			"truncated",
+			`[` + strings.Repeat(`1,`, 100) + `x` + strings.Repeat(`,1`, 100) + `]`, 6,
+			`...1,1,1,x,1,1,1...` + "\n" +
+				`         ^`,
+		}, {
+			"truncated_left",
+			strings.Repeat(` `, 100) + `x`, 4,
+			`...    x` + "\n" +
+				`       ^`,
+		}, {
+			"truncated_multi-byte",
+			`["äää", ` + `x, "äää"]`, 5,
+			`...ä", x, "ä...` + "\n" +
+				`       ^`,
+		}, {
+			"truncated_within multi-byte",
+			`[x, "` + "\U0001F600" + `"]`, 4,
+			`[x, "` + "\U0001F600" + `...` + "\n" +
+				` ^`,
+		}, {
+			"non-printable",
+			"[\"\x01\t\u2028\xff\", x]", 0,
+			`["\x01\x09\u2028\xff", x]` + "\n" +
+				`                       ^`,
+		}, {
+			"multi-byte",
+			`{"ä€😀": x}`, 0,
+			`{"ä€😀": x}` + "\n" +
+				`        ^`,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			err := NewParser(0).Validate(tt.in, Options{
+				AllowControlChars: true,
+			})
+			require.NotZero(t, err.DebugCode)
+			require.Equal(t, tt.expect, err.Excerpt(tt.in, tt.contextBytes))
+		})
+	}
+}
+
+func TestErrExcerptCR(t *testing.T) {
+	// The caret must not be placed beyond the line
+	// when the CR of CRLF is the offending byte
+	in := "{\"a\": \"b\r\n\"}"
+	err := NewParser(0).Validate(in, Options{})
+	require.Equal(t, ErrControlCharInString, err.DebugCode)
+	require.Equal(t,
+		`{"a": "b\x0d`+"\n"+
+			`        ^`,
+		err.Excerpt(in, 0),
+	)
+}
+
+func TestErrExcerptOutOfRange(t *testing.T) {
+	err := Err{DebugCode: ErrExpectedValue, Offset: 5}
+	require.Equal(t, "", err.Excerpt(`[1]`, 0))
+}
@@ -0,0 +1,103 @@
+package jsonvalidate
+
+// Handler receives the tokens of the input as they are validated.
+// Each method is called with the raw token as it appears in the input,
+// including the quotes and escape sequences of strings and keys,
+// and its offset in the input. Tokens are reported once they are
+// known to be valid, a handler returning an error aborts validation
+// with ErrHandlerAborted wrapping the error
+type Handler interface {
+	// BeginObject is called for the '{' beginning an object
+	BeginObject(token string, offset int) error
+
+	// Key is called for an object key
+	Key(token string, offset int) error
+
+	// BeginArray is called for the '[' beginning an array
+	BeginArray(token string, offset int) error
+
+	// String is called for a string value
+	String(token string, offset int) error
+
+	// Number is called for a number value,
+	// including NaN and Infinity if allowed
+	Number(token string, offset int) error
+
+	// Bool is called for true and false
+	Bool(token string, offset int) error
+
+	// Null is called for null
+	Null(token string, offset int) error
+
+	// End is called for the '}' or ']' ending
+	// the current object or array
+	End(token string, offset int) error
+}
+
+// ValidateWithBytes is similar to ValidateWith
+// but validates the given byte slice
+func (pr *Parser) ValidateWithBytes(s []byte, opts Options, h Handler) Err {
+	return pr.validateWith(b2s(s), opts, h)
+}
+
+// ValidateWith is similar to Validate but calls h for each token
+// of the input in order. The tokens passed to h refer to s.
+// A nil handler is allowed
+func (pr *Parser) ValidateWith(s string, opts Options, h Handler) Err {
+	return pr.validateWith(s, opts, h)
+}
+
+func (pr *Parser) validateWith(input string, opts Options, h Handler) Err {
+	if err := checkInputLength(input, opts); err.DebugCode != 0 {
+		return err
+	}
+
+	var sc scanner
+	sc.init(pr.stackPool, opts, false)
+	defer pr.stackPool.Release(sc.stk)
+	sc.h = h
+	_, err := sc.scan(input, true)
+	if err.DebugCode != 0 {
+		err.setPosition(input)
+	}
+	return err
+}
+
+// handle calls the handler for the valid token of length n
+// at the beginning of s which is the unscanned tail of input.
+// key is set for object keys.
+// If the handler fails the results of scan are returned
+func (sc *scanner) handle(input, s string, n int, key bool) (int, Err) {
+	token := s[:n]
+	offset := sc.offset + len(input) - len(s)
+	var err error
+	switch {
+	case key:
+		err = sc.h.Key(token, offset)
+	case token[0] == '{':
+		err = sc.h.BeginObject(token, offset)
+	case token[0] == '[':
+		err = sc.h.BeginArray(token, offset)
+	case token[0] == '}', token[0] == ']':
+		err = sc.h.End(token, offset)
+	case token[0] == '"', token[0] == '\'':
+		err = sc.h.String(token, offset)
+	case token[0] == 't', token[0] == 'f':
+		err = sc.h.Bool(token, offset)
+	case token[0] == 'n':
+		err = sc.h.Null(token, offset)
+	default:
+		err = sc.h.Number(token, offset)
+	}
+	if err == nil {
+		return 0, Err{}
+	}
+	state := stateNext
+	if key {
+		// Report the key as part of the path
+		state = stateColon
+	}
+	n, verr := sc.error(ErrHandlerAborted, state, input, s)
+	verr.Cause = err
+	return n, verr
+}
@@ -0,0 +1,221 @@
+package jsonvalidate
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// recordingHandler records the events as "kind token offset"
+// and fails at the event with the index failAt, if any
+type recordingHandler struct {
+	events []string
+	failAt int
+	err    error
+}
+
+func (h *recordingHandler) event(kind, token string, offset int) error {
+	h.events = append(h.events, fmt.Sprintf("%s %s %d", kind, token, offset))
+	if len(h.events)-1 == h.failAt {
+		return h.err
+	}
+	return nil
+}
+
+func (h *recordingHandler) BeginObject(token string, offset int) error {
+	return h.event("begin_object", token, offset)
+}
+
+func (h *recordingHandler) Key(token string, offset int) error {
+	return h.event("key", token, offset)
+}
+
+func (h *recordingHandler) BeginArray(token string, offset int) error {
+	return h.event("begin_array", token, offset)
+}
+
+func (h *recordingHandler) String(token string, offset int) error {
+	return h.event("string", token, offset)
+}
+
+func (h *recordingHandler) Number(token string, offset int) error {
+	return h.event("number", token, offset)
+}
+
+func (h *recordingHandler) Bool(token string, offset int) error {
+	return h.event("bool", token, offset)
+}
+
+func (h *recordingHandler) Null(token string, offset int) error {
+	return h.event("null", token, offset)
+}
+
+func (h *recordingHandler) End(token string, offset int) error {
+	return h.event("end", token, offset)
+}
+
+func TestValidateWith(t *testing.T) {
+	for _, tt := range []struct {
+		name   string
+		in     string
+		opts   Options
+		expect []string
+	}{
+		{"string", `"a\"b"`, Options{}, []string{`string "a\"b" 0`}},
+		{"number", ` -1.5e3 `, Options{}, []string{`number -1.5e3 1`}},
+		{"literals", `[true,false,null]`, Options{}, []string{
+			`begin_array [ 0`,
+			`bool true 1`,
+			`bool false 6`,
+			`null null 12`,
+			`end ] 16`,
+		}},
+		{"empty_containers", `[{},[]]`, Options{}, []string{
+			`begin_array [ 0`,
+			`begin_object { 1`,
+			`end } 2`,
+			`begin_array [ 4`,
+			`end ] 5`,
+			`end ] 6`,
+		}},
+		{"object", `{"a": {"bc": [1, "x"]}, "d": null}`, Options{}, []string{
+			`begin_object { 0`,
+			`key "a" 1`,
+			`begin_object { 6`,
+			`key "bc" 7`,
+			`begin_array [ 13`,
+			`number 1 14`,
+			`string "x" 17`,
+			`end ] 20`,
+			`end } 21`,
+			`key "d" 24`,
+			`null null 29`,
+			`end } 33`,
+		}},
+		{"trailing_commas", `{"a":[1,],}`, Options{AllowTrailingCommas: true}, []string{
+			`begin_object { 0`,
+			`key "a" 1`,
+			`begin_array [ 5`,
+			`number 1 6`,
+			`end ] 8`,
+			`end } 10`,
+		}},
+		{"multiple_values", `1 "a" []`, Options{AllowMultipleValues: true}, []string{
+			`number 1 0`,
+			`string "a" 2`,
+			`begin_array [ 6`,
+			`end ] 7`,
+		}},
+		{"non_finite", `[NaN,-Infinity]`, Options{AllowNonFiniteNumbers: true}, []string{
+			`begin_array [ 0`,
+			`number NaN 1`,
+			`number -Infinity 5`,
+			`end ] 14`,
+		}},
+		{"json5", `{a: 'x', "b": 0x1F,}`, Options{Dialect: JSON5}, []string{
+			`begin_object { 0`,
+			`key a 1`,
+			`string 'x' 4`,
+			`key "b" 9`,
+			`number 0x1F 14`,
+			`end } 19`,
+		}},
+		{"bom", "\xef\xbb\xbf[]", Options{BOM: Skip}, []string{
+			`begin_array [ 3`,
+			`end ] 4`,
+		}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			h := &recordingHandler{failAt: -1}
+			require.Zero(t, NewParser(0).ValidateWith(tt.in, tt.opts, h))
+			require.Equal(t, tt.expect, h.events)
+
+			h = &recordingHandler{failAt: -1}
+			require.Zero(t, NewParser(0).ValidateWithBytes([]byte(tt.in), tt.opts, h))
+			require.Equal(t, tt.expect, h.events)
+		})
+	}
+}
+
+func TestValidateWithInvalid(t *testing.T) {
+	// Tokens are reported up to the error
+	h := &recordingHandler{failAt: -1}
+	err := NewParser(0).ValidateWith(`[1,{"a":tru}]`, Options{}, h)
+	require.Equal(t,
+		Err{DebugCode: ErrInvalidTrue, Offset: 8, Line: 1, Column: 9, Path: "/1/a"},
+		err,
+	)
+	require.Equal(t, []string{
+		`begin_array [ 0`,
+		`number 1 1`,
+		`begin_object { 3`,
+		`key "a" 4`,
+	}, h.events)
+}
+
+func TestValidateWithHandlerError(t *testing.T) {
+	errStop := errors.New("stop")
+	in := "{\"a\": [1, {\"b\": 2}],\n\"c\": 3}"
+	for _, tt := range []struct {
+		name   string
+		failAt int
+		expect Err
+	}{
+		{"begin_object", 0,
+			Err{Offset: 0, Line: 1, Column: 1}},
+		{"key", 1,
+			Err{Offset: 1, Line: 1, Column: 2, Path: "/a"}},
+		{"begin_array", 2,
+			Err{Offset: 6, Line: 1, Column: 7, Path: "/a"}},
+		{"number", 3,
+			Err{Offset: 7, Line: 1, Column: 8, Path: "/a/0"}},
+		{"nested_object", 4,
+			Err{Offset: 10, Line: 1, Column: 11, Path: "/a/1"}},
+		{"nested_key", 5,
+			Err{Offset: 11, Line: 1, Column: 12, Path: "/a/1/b"}},
+		{"nested_number", 6,
+			Err{Offset: 16, Line: 1, Column: 17, Path: "/a/1/b"}},
+		{"nested_end", 7,
+			Err{Offset: 17, Line: 1, Column: 18, Path: "/a/1"}},
+		{"array_end", 8,
+			Err{Offset: 18, Line: 1, Column: 19, Path: "/a"}},
+		{"last_key", 9,
+			Err{Offset: 21, Line: 2, Column: 1, Path: "/c"}},
+		{"end", 11,
+			Err{Offset: 27, Line: 2, Column: 7}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			h := &recordingHandler{failAt: tt.failAt, err: errStop}
+			err := NewParser(0).ValidateWith(in, Options{}, h)
+			tt.expect.DebugCode = ErrHandlerAborted
+			tt.expect.Cause = errStop
+			require.Equal(t, tt.expect, err)
+			require.Len(t, h.events, tt.failAt+1)
+
+			require.True(t, errors.Is(err, ErrHandlerAborted))
+			require.True(t, errors.Is(err, errStop))
+			require.False(t, errors.Is(err, ErrExpectedValue))
+		})
+	}
+}
+
+func TestValidateWithNilHandler(t *testing.T) {
+	require.Zero(t, NewParser(0).ValidateWith(`{"a":[1]}`, Options{}, nil))
+	require.Equal(t,
+		Err{DebugCode: ErrUnexpectedEOF, Offset: 8, Line: 1, Column: 9, Path: "/a/1"},
+		NewParser(0).ValidateWith(`{"a":[1,`, Options{}, nil),
+	)
+}
+
+func TestErrCause(t *testing.T) {
+	err := Err{
+		DebugCode: ErrHandlerAborted,
+		Offset:    3,
+		Line:      1,
+		Column:    4,
+		Cause:     errors.New("stop"),
+	}
+	require.Equal(t, "aborted by handler: stop at line 1, column 4 (offset 3)", err.Error())
+}
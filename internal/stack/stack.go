@@ -2,6 +2,7 @@ package stack
 
 import (
 	"sync"
+	"unsafe"
 )
 
 // ContainerType represents the type of a container
@@ -9,7 +10,8 @@ type ContainerType byte
 
 // Container types
 const (
-	_ ContainerType = iota
+	// Void is the type of the top of an empty stack
+	Void ContainerType = iota
 	Object
 	Array
 )
@@ -18,6 +20,8 @@ const (
 type Layer struct {
 	numElements   int
 	objectKeys    map[string]struct{}
+	key           string
+	keyBuf        []byte
 	containerType ContainerType
 }
 
@@ -25,7 +29,9 @@ type Layer struct {
 type Stack struct {
 	elements  []Layer
 	endOffset int
+	used      int
 	trackKeys bool
+	copyKeys  bool
 }
 
 // Top returns the current top level stack
@@ -37,41 +43,71 @@ func (s *Stack) Top() (
 	if s.endOffset < 1 {
 		return 0, 0, 0
 	}
-	x := s.elements[s.endOffset-1]
+	x := &s.elements[s.endOffset-1]
 	return x.containerType, x.numElements, s.endOffset
 }
 
+// At returns the layer at the given level counting from the bottom,
+// key is the key of the last field pushed onto an object layer
+func (s *Stack) At(level int) (
+	containerType ContainerType,
+	numElements int,
+	key string,
+) {
+	x := &s.elements[level]
+	return x.containerType, x.numElements, x.key
+}
+
 // Push pushes a new stack on top of the current top level stack
 func (s *Stack) Push(o ContainerType) {
-	newLayer := Layer{
-		containerType: o,
-		numElements:   0,
-	}
 	if s.endOffset >= len(s.elements) {
 		// Grow stack
-		s.elements = append(s.elements, newLayer)
-	} else {
-		s.elements[s.endOffset] = newLayer
+		s.elements = append(s.elements, Layer{})
 	}
+	// Reset the layer keeping the key buffer for reuse
+	x := &s.elements[s.endOffset]
+	x.containerType = o
+	x.numElements = 0
+	x.objectKeys = nil
+	x.key = ""
 	s.endOffset++
+	if s.endOffset > s.used {
+		s.used = s.endOffset
+	}
 }
 
 // PushField increments the number of elements of the current
-// top level stack returning true if the field was pushed,
-// otherwise returning false indicating that a field with a
-// similar name was already registered
+// top level stack and makes name its current key returning true
+// if the field was pushed, otherwise returning false indicating that
+// a field with a similar name was already registered.
+// Names are only registered if the stack keeps track of the keys
 func (s *Stack) PushField(name string) bool {
-	s.elements[s.endOffset-1].numElements++
-	k := s.elements[s.endOffset-1].objectKeys
-	if k == nil {
-		s.elements[s.endOffset-1].objectKeys = map[string]struct{}{
-			name: struct{}{},
+	x := &s.elements[s.endOffset-1]
+	x.numElements++
+	if !s.trackKeys {
+		if s.copyKeys {
+			x.keyBuf = append(x.keyBuf[:0], name...)
+			name = b2s(x.keyBuf)
 		}
+		x.key = name
 		return true
-	} else if _, ok := k[name]; ok {
+	}
+
+	if _, ok := x.objectKeys[name]; ok {
+		x.key = name
 		return false
 	}
-	k[name] = struct{}{}
+	if s.copyKeys {
+		name = cloneString(name)
+	}
+	x.key = name
+	if x.objectKeys == nil {
+		x.objectKeys = map[string]struct{}{
+			name: struct{}{},
+		}
+		return true
+	}
+	x.objectKeys[name] = struct{}{}
 	return true
 }
 
@@ -97,11 +133,14 @@ func (s *Stack) reset(maxInitStackLen int) {
 	// Reset stack length if necessary
 	if len(s.elements) > maxInitStackLen {
 		s.elements = make([]Layer, maxInitStackLen)
-	} else if s.trackKeys {
-		for i := 0; i < len(s.elements); i++ {
+	} else {
+		// Don't keep the keys and thus the input alive
+		for i := 0; i < s.used; i++ {
 			s.elements[i].objectKeys = nil
+			s.elements[i].key = ""
 		}
 	}
+	s.used = 0
 }
 
 // Pool holds a pool of stacks
@@ -124,10 +163,13 @@ func NewPool(maxInitStackLen int) *Pool {
 	}
 }
 
-// Acquire acquires and returns a new stack which must be released later
-func (p *Pool) Acquire(trackKeys bool) *Stack {
+// Acquire acquires and returns a new stack which must be released later.
+// The keys must be copied if the memory they refer to
+// is reused while the stack is in use
+func (p *Pool) Acquire(trackKeys, copyKeys bool) *Stack {
 	s := p.pool.Get().(*Stack)
 	s.trackKeys = trackKeys
+	s.copyKeys = copyKeys
 	return s
 }
 
@@ -136,3 +178,14 @@ func (p *Pool) Release(s *Stack) {
 	s.reset(p.maxInitStackLen)
 	p.pool.Put(s)
 }
+
+// cloneString returns a copy of s that doesn't share memory with s
+func cloneString(s string) string {
+	b := make([]byte, len(s))
+	copy(b, s)
+	return b2s(b)
+}
+
+func b2s(b []byte) string {
+	return *(*string)(unsafe.Pointer(&b))
+}
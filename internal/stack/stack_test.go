@@ -9,7 +9,7 @@ import (
 func TestStackResetExceedMaxLen(t *testing.T) {
 	const maxInitStackLen = 8
 	p := NewPool(maxInitStackLen)
-	s := p.Acquire(true)
+	s := p.Acquire(true, false)
 
 	// {"foo": <>, "bar": [[<>[<>, {"baz": [[ [ [] ] ]]} ]]]}
 	s.Push(Object)
@@ -40,7 +40,7 @@ func TestStackResetExceedMaxLen(t *testing.T) {
 func TestStackReset(t *testing.T) {
 	const maxInitStackLen = 64
 	p := NewPool(maxInitStackLen)
-	s := p.Acquire(true)
+	s := p.Acquire(true, false)
 
 	// {"foo": <>, "bar": [[<>[<>, {"baz": [[ [ [] ] ]]} ]]]}
 	s.Push(Object)
@@ -72,3 +72,37 @@ func TestStackReset(t *testing.T) {
 		require.Zero(t, e.objectKeys)
 	}
 }
+
+func TestStackAtCopyKeys(t *testing.T) {
+	for _, trackKeys := range []bool{true, false} {
+		p := NewPool(8)
+		s := p.Acquire(trackKeys, true)
+
+		// {"foo": [<>, {"bar": <>
+		name := []byte("foo")
+		s.Push(Object)
+		require.True(t, s.PushField(b2s(name)))
+		s.Push(Array)
+		s.PushElement()
+		s.Push(Object)
+		require.True(t, s.PushField("bar"))
+
+		// Overwrite the memory the first key was taken from
+		copy(name, "xxx")
+
+		typ, numElements, key := s.At(0)
+		require.Equal(t, Object, typ)
+		require.Equal(t, 1, numElements)
+		require.Equal(t, "foo", key)
+
+		typ, numElements, key = s.At(1)
+		require.Equal(t, Array, typ)
+		require.Equal(t, 1, numElements)
+		require.Zero(t, key)
+
+		_, _, key = s.At(2)
+		require.Equal(t, "bar", key)
+
+		p.Release(s)
+	}
+}
@@ -0,0 +1,457 @@
+package jsonvalidate
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Dialect selects the grammar the input is written in
+type Dialect byte
+
+// Dialects
+const (
+	// JSON is the default dialect, see Profile for its variants
+	JSON Dialect = iota
+
+	// JSON5 accepts JSON5 as defined by https://spec.json5.org.
+	// Next to JSON it allows ECMAScript identifiers as object keys,
+	// single-quoted strings, additional escape sequences, line continuations,
+	// hexadecimal numbers, leading and trailing decimal points,
+	// a leading '+', Infinity and NaN, comments, trailing commas
+	// and additional whitespace
+	JSON5
+)
+
+var dialectNames = map[Dialect]string{
+	JSON:  "json",
+	JSON5: "json5",
+}
+
+// String returns the name of the dialect
+func (d Dialect) String() string {
+	if n, ok := dialectNames[d]; ok {
+		return n
+	}
+	return "dialect (" + strconv.Itoa(int(d)) + ")"
+}
+
+// scanScalar5 scans the JSON5 string or number at the beginning of s
+// which is the unscanned tail of input and returns the tail following it.
+// If scanning is suspended or failed done is set
+// and n and err are the results of scan
+func (sc *scanner) scanScalar5(
+	input, s string,
+	final bool,
+) (tail string, n int, err Err, done bool) {
+	var code ErrorCode
+	switch s[0] {
+	case '"', '\'':
+		return sc.scanStringValue(input, s, final)
+
+	case '+', '-', '.', 'I', 'N', '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
+		tail, code = scanNumber5(s, sc.opts.MaxNumberDigits)
+		if !final && len(tail) == 0 {
+			// The number might continue in the next part
+			sc.state = stateValue
+			return "", len(input) - len(s), Err{}, true
+		}
+		if code == ErrMaxNumberDigits {
+			// Report the first digit beyond the limit
+			n, err = sc.errorAt(code, stateNext, input, s, tail)
+			return "", n, err, true
+		}
+		if code != 0 {
+			n, err = sc.error(code, stateNext, input, s)
+			return "", n, err, true
+		}
+		if sc.rules.validateNumbers && !isDoubleNumber5(s[:len(s)-len(tail)]) {
+			n, err = sc.error(ErrNumberOutOfRange, stateNext, input, s)
+			return "", n, err, true
+		}
+		return tail, 0, Err{}, false
+	}
+	n, err = sc.error(ErrExpectedValue, stateNext, input, s)
+	return "", n, err, true
+}
+
+// scanKey5 scans the JSON5 object key at the beginning of s
+// which is either a string or an ECMAScript IdentifierName.
+// The raw key is returned along with the tail following it and
+// the length of the opening quote, which is 0 for identifiers
+func scanKey5(s string, maxLen int) (string, string, int, ErrorCode) {
+	if s[0] == '"' || s[0] == '\'' {
+		sv, tail, code := scanString5(s[1:], s[0], maxLen)
+		switch code {
+		case ErrMaxStringBytes:
+			code = ErrMaxKeyBytes
+		case ErrControlCharInString:
+			code = ErrControlCharInKey
+		}
+		return sv, tail, 1, code
+	}
+
+	w := s
+	if maxLen > 0 && len(w) > maxLen {
+		// The name must end within the first maxLen+1 bytes
+		w = w[:maxLen+1]
+	}
+	sv, tail, code := scanIdentifier(w)
+	if len(tail) == 0 && len(w) < len(s) {
+		// The name or an escape sequence of it reaches beyond the limit
+		return "", s[maxLen:], 0, ErrMaxKeyBytes
+	}
+	if code == 0 {
+		tail = s[len(sv):]
+	}
+	return sv, tail, 0, code
+}
+
+// scanIdentifier scans the ECMAScript 5.1 IdentifierName
+// at the beginning of s. The returned tail is empty if s ends
+// within an escape sequence of the name
+func scanIdentifier(s string) (string, string, ErrorCode) {
+	i := 0
+	for i < len(s) {
+		if s[i] == '\\' {
+			// Only \u escape sequences are allowed
+			switch {
+			case len(s)-i < 2:
+				return "", "", ErrInvalidEscape
+			case s[i+1] != 'u':
+				return "", s[i:], ErrInvalidEscape
+			case len(s)-i < 6:
+				return "", "", ErrShortUnicodeEscape
+			}
+			v, err := strconv.ParseUint(s[i+2:i+6], 16, 16)
+			if err != nil {
+				return "", s[i:], ErrInvalidUnicodeEscape
+			}
+			if !isIdentifierRune(rune(v), i == 0) {
+				return "", s[i:], ErrInvalidIdentifier
+			}
+			i += 6
+			continue
+		}
+
+		r, size := rune(s[i]), 1
+		if r >= utf8.RuneSelf {
+			r, size = utf8.DecodeRuneInString(s[i:])
+		}
+		if !isIdentifierRune(r, i == 0) {
+			break
+		}
+		i += size
+	}
+	if i == 0 {
+		return "", s, ErrExpectedKey
+	}
+	return s[:i], s[i:], 0
+}
+
+// isIdentifierRune returns true if r may begin an ECMAScript 5.1
+// identifier, or if start isn't set, may be part of it
+func isIdentifierRune(r rune, start bool) bool {
+	switch {
+	case r < utf8.RuneSelf:
+		return r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' ||
+			r == '$' || r == '_' || !start && r >= '0' && r <= '9'
+	case unicode.In(r,
+		unicode.Lu, unicode.Ll, unicode.Lt, unicode.Lm, unicode.Lo, unicode.Nl,
+	):
+		return true
+	case start:
+		return false
+	}
+	// Zero width non-joiner and joiner
+	return r == 0x200C || r == 0x200D ||
+		unicode.In(r, unicode.Mn, unicode.Mc, unicode.Nd, unicode.Pc)
+}
+
+// scanString5 is similar to scanString but scans a JSON5 string
+// enclosed in quote. Unescaped line terminators are reported
+// as ErrControlCharInString
+func scanString5(s string, quote byte, maxLen int) (string, string, ErrorCode) {
+	if maxLen > 0 && len(s) > maxLen {
+		scan := scanDoubleQuoted5
+		if quote == '\'' {
+			scan = scanSingleQuoted5
+		}
+		return scanLimited(s, maxLen, scan, ErrMaxStringBytes)
+	}
+
+	raw, tail, errCode := scanRawString5(s, quote)
+	if errCode != 0 {
+		return raw, tail, errCode
+	}
+	rs := raw
+	for {
+		n := strings.IndexByte(rs, '\\')
+		if n < 0 {
+			n = len(rs)
+		}
+		if lineTerminator(rs[:n]) >= 0 {
+			return raw, tail, ErrControlCharInString
+		}
+		if n == len(rs) {
+			return raw, tail, 0
+		}
+		// The raw string can't end with an unescaped backslash
+		l, code := scanEscape5(rs[n+1:])
+		if code != 0 {
+			return raw, tail, code
+		}
+		rs = rs[n+1+l:]
+	}
+}
+
+// scanEscape5 is similar to scanEscape
+// but scans a JSON5 escape sequence
+func scanEscape5(s string) (int, ErrorCode) {
+	switch s[0] {
+	case '0':
+		if len(s) > 1 && s[1] >= '0' && s[1] <= '9' {
+			// Octal escape sequences aren't allowed
+			return 0, ErrInvalidEscape
+		}
+	case '1', '2', '3', '4', '5', '6', '7', '8', '9':
+		return 0, ErrInvalidEscape
+	case 'x':
+		if len(s) < 3 || !isHexDigit(s[1]) || !isHexDigit(s[2]) {
+			return 0, ErrInvalidHexEscape
+		}
+		return 3, 0
+	case 'u':
+		return scanEscape(s)
+	case '\r':
+		// Line continuation
+		if len(s) > 1 && s[1] == '\n' {
+			return 2, 0
+		}
+	}
+	// Any other character stands for itself,
+	// an escaped line terminator continues the line
+	return 1, 0
+}
+
+// escapeLen5 is similar to escapeLen
+// but applies to JSON5 escape sequences
+func escapeLen5(c byte) int {
+	switch c {
+	case 'u':
+		return 5
+	case 'x':
+		return 3
+	case '0', '\r':
+		return 2
+	}
+	return 1
+}
+
+func scanDoubleQuoted5(s string, maxLen int) (string, string, ErrorCode) {
+	return scanString5(s, '"', maxLen)
+}
+
+func scanSingleQuoted5(s string, maxLen int) (string, string, ErrorCode) {
+	return scanString5(s, '\'', maxLen)
+}
+
+// scanRawString5 is similar to scanRawString
+// but scans a string enclosed in quote
+func scanRawString5(s string, quote byte) (string, string, ErrorCode) {
+	n := strings.IndexByte(s, quote)
+	if n >= 0 && strings.IndexByte(s[:n], '\\') < 0 {
+		// Fast path. No escape sequences.
+		return s[:n], s[n+1:], 0
+	}
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case quote:
+			return s[:i], s[i+1:], 0
+		case '\\':
+			i++
+		}
+	}
+	// Missing closing quote
+	return s, "", ErrUnterminatedString
+}
+
+// lineTerminator returns the index of the first unescaped LF or CR
+// in the raw JSON5 string s, or -1 if there is none
+func lineTerminator(s string) int {
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\n', '\r':
+			return i
+		case '\\':
+			if s[i+1] == '\r' && i+2 < len(s) && s[i+2] == '\n' {
+				i++
+			}
+			i++
+		}
+	}
+	return -1
+}
+
+// scanNumber5 is similar to scanNumber but scans a JSON5 number
+func scanNumber5(s string, maxDigits int) (string, ErrorCode) {
+	if s[0] == '-' || s[0] == '+' {
+		s = s[1:]
+		if len(s) == 0 {
+			// missing number after sign
+			return s, ErrExpectedDigit
+		}
+	}
+	switch s[0] {
+	case 'I':
+		return scanLiteral(s, "Infinity", ErrInvalidInfinity)
+	case 'N':
+		return scanLiteral(s, "NaN", ErrInvalidNaN)
+	}
+
+	if len(s) > 1 && s[0] == '0' && (s[1] == 'x' || s[1] == 'X') {
+		// Hexadecimal integer
+		s = s[2:]
+		i := 0
+		for i < len(s) && isHexDigit(s[i]) {
+			i++
+		}
+		if i == 0 {
+			return s, ErrExpectedHexDigit
+		}
+		if maxDigits > 0 && i > maxDigits {
+			return s[maxDigits:], ErrMaxNumberDigits
+		}
+		return s[i:], 0
+	}
+
+	i := 0
+	for i < len(s) && s[i] >= '0' && s[i] <= '9' {
+		i++
+	}
+	if i == 0 && s[0] != '.' {
+		// non 0..9 digit
+		return s, ErrExpectedDigit
+	}
+	if i > 1 && s[0] == '0' {
+		// unexpected number starting from 0
+		return s, ErrLeadingZero
+	}
+	digits := i
+	if maxDigits > 0 && digits > maxDigits {
+		return s[maxDigits:], ErrMaxNumberDigits
+	}
+	if i < len(s) && s[i] == '.' {
+		// Either the integer or the fractional part may be omitted
+		s = s[i+1:]
+		i = 0
+		for i < len(s) && s[i] >= '0' && s[i] <= '9' {
+			i++
+		}
+		if i == 0 && digits == 0 {
+			// Expecting 0..9 digit in fractional part
+			return s, ErrExpectedFractionDigit
+		}
+		digits += i
+		if maxDigits > 0 && digits > maxDigits {
+			return s[i-(digits-maxDigits):], ErrMaxNumberDigits
+		}
+	}
+	if i >= len(s) {
+		return "", 0
+	}
+	if s[i] == 'e' || s[i] == 'E' {
+		// The exponent is the same as in JSON
+		return scanExponent(s[i+1:], digits, maxDigits)
+	}
+	return s[i:], 0
+}
+
+// scanExponent scans the digits of the exponent at the beginning of s
+// following the given number of digits of the mantissa
+func scanExponent(s string, digits, maxDigits int) (string, ErrorCode) {
+	if len(s) == 0 {
+		// Missing exponent part
+		return s, ErrExpectedExponentDigit
+	}
+	if s[0] == '-' || s[0] == '+' {
+		s = s[1:]
+		if len(s) == 0 {
+			// Missing exponent part
+			return s, ErrExpectedExponentDigit
+		}
+	}
+	i := 0
+	for i < len(s) && s[i] >= '0' && s[i] <= '9' {
+		i++
+	}
+	if i == 0 {
+		// Expecting 0..9 digit in exponent part
+		return s, ErrExpectedExponentDigit
+	}
+	digits += i
+	if maxDigits > 0 && digits > maxDigits {
+		return s[i-(digits-maxDigits):], ErrMaxNumberDigits
+	}
+	return s[i:], 0
+}
+
+// scanLiteral scans the literal at the beginning of s returning
+// code if s doesn't begin with it. The tail is empty if s is
+// a prefix of the literal since it might continue in the next part
+func scanLiteral(s, literal string, code ErrorCode) (string, ErrorCode) {
+	if strings.HasPrefix(s, literal) {
+		return s[len(literal):], 0
+	}
+	if strings.HasPrefix(literal, s) {
+		return "", code
+	}
+	return s, code
+}
+
+// isDoubleNumber5 is similar to isDoubleNumber but checks
+// a valid JSON5 number. Infinity and NaN aren't accepted
+func isDoubleNumber5(s string) bool {
+	if s[0] == '+' {
+		s = s[1:]
+	}
+	digits := s
+	if digits[0] == '-' {
+		digits = digits[1:]
+	}
+	switch {
+	case digits[0] == 'I', digits[0] == 'N':
+		return false
+	case len(digits) > 1 && (digits[1] == 'x' || digits[1] == 'X'):
+		v, err := strconv.ParseUint(digits[2:], 16, 64)
+		return err == nil && v <= 1<<53-1
+	}
+	return isDoubleNumber(s)
+}
+
+func isHexDigit(c byte) bool {
+	return c >= '0' && c <= '9' || c >= 'a' && c <= 'f' || c >= 'A' && c <= 'F'
+}
+
+// skipWS5 skips the whitespace JSON5 allows in addition to JSON's:
+// vertical tab, form feed, non-breaking space, the byte order mark,
+// line and paragraph separators and other space separators
+func skipWS5(s string) string {
+	for len(s) > 0 {
+		switch c := s[0]; {
+		case c == '\v' || c == '\f':
+			s = s[1:]
+		case c >= utf8.RuneSelf:
+			r, size := utf8.DecodeRuneInString(s)
+			if r != '\uFEFF' && r != '\u2028' && r != '\u2029' &&
+				!unicode.Is(unicode.Zs, r) {
+				return s
+			}
+			s = s[size:]
+		default:
+			return s
+		}
+	}
+	return s
+}
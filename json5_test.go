@@ -0,0 +1,171 @@
+package jsonvalidate
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestJSON5(t *testing.T) {
+	opts := Options{Dialect: JSON5}
+	for _, tt := range []struct {
+		name string
+		in   string
+	}{
+		{"identifier_keys", `{foo: 1, $bar: 2, _baz: 3, a1_$: 4}`},
+		{"unicode_identifier_keys", "{\xc3\xa4\xc3\xb6: 1, \xe5\x90\x8d\xe5\x89\x8d: 2}"},
+		{"identifier_combining_mark", "{a\xcc\x81: 1}"},
+		{"escaped_identifier_key", `{\u0061b: 1, a\u0062c: 2}`},
+		{"reserved_word_keys", `{null: 1, true: 2, Infinity: 3, NaN: 4}`},
+		{"single_quoted_key", `{'a': 1, 'b"c': 2}`},
+		{"single_quoted_string", `['foo', 'it\'s', '"']`},
+		{"escapes", `["\v\0\x41\'\q\/", '\"']`},
+		{"line_continuation", "['a\\\nb', \"c\\\r\nd\", 'e\\\rf', 'g\\\xe2\x80\xa8h']"},
+		{"line_separator_in_string", "['a\xe2\x80\xa8b\xe2\x80\xa9c']"},
+		{"tab_in_string", "'a\tb'"},
+		{"hex_numbers", `[0x0, 0xff, 0XAbC, -0x1F, +0x2]`},
+		{"decimal_points", `[.5, 5., -.5, +5., 0.5e3, .5e-1, 5.e+2]`},
+		{"leading_plus", `[+1, +0, +1.5e3]`},
+		{"infinity_nan", `[Infinity, -Infinity, +Infinity, NaN, -NaN, +NaN]`},
+		{"comments", "// a\n{/* b */ a: 1}"},
+		{"trailing_commas", `{a: [1, 2,], b: {c: 3,},}`},
+		{"whitespace", "{\va:\f1,\xc2\xa0b:\xef\xbb\xbf2\xe2\x80\xa8,\xe3\x80\x80c: 3\xe2\x80\xa9}"},
+		{"spec_example", `{
+  // comments
+  unquoted: 'and you can quote me on that',
+  singleQuotes: 'I can use "double quotes" here',
+  lineBreaks: "Look, Mom! \
+No \\n's!",
+  hexadecimal: 0xdecaf,
+  leadingDecimalPoint: .8675309, andTrailing: 8675309.,
+  positiveSign: +1,
+  trailingComma: 'in objects', andIn: ['arrays',],
+  "backwardsCompatible": "with JSON",
+}`},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Zero(t, validateParts(t, opts, tt.in))
+			require.NotZero(t, NewParser(0).Validate(tt.in, Options{}).DebugCode)
+		})
+	}
+}
+
+func TestJSON5Invalid(t *testing.T) {
+	for _, tt := range []struct {
+		name   string
+		in     string
+		opts   Options
+		expect Err
+	}{
+		{"identifier_digit_start", `{1a: 1}`, Options{},
+			Err{DebugCode: ErrExpectedKey, Offset: 1, Line: 1, Column: 2}},
+		{"identifier_hyphen", `{a-b: 1}`, Options{},
+			Err{DebugCode: ErrExpectedColon, Offset: 2, Line: 1, Column: 3, Path: "/a"}},
+		{"identifier_invalid_escape", `{a\x41: 1}`, Options{},
+			Err{DebugCode: ErrInvalidEscape, Offset: 1, Line: 1, Column: 2}},
+		{"identifier_escaped_space", `{a\u0020b: 1}`, Options{},
+			Err{DebugCode: ErrInvalidIdentifier, Offset: 1, Line: 1, Column: 2}},
+		{"identifier_escaped_digit_start", `{\u0031: 1}`, Options{},
+			Err{DebugCode: ErrInvalidIdentifier, Offset: 1, Line: 1, Column: 2}},
+		{"identifier_short_escape", `{a\u006`, Options{},
+			Err{DebugCode: ErrShortUnicodeEscape, Offset: 1, Line: 1, Column: 2}},
+		{"identifier_bad_hex", `{a\u00zz: 1}`, Options{},
+			Err{DebugCode: ErrInvalidUnicodeEscape, Offset: 1, Line: 1, Column: 2}},
+		{"identifier_eof", `{abc`, Options{},
+			Err{DebugCode: ErrUnexpectedEOF, Offset: 4, Line: 1, Column: 5, Path: "/abc"}},
+		{"duplicate_identifier_key", `{a: 1, 'a': 2}`, Options{},
+			Err{DebugCode: ErrDuplicateKey, Offset: 7, Line: 1, Column: 8, Path: "/a"}},
+		{"duplicate_escaped_identifier_key", `{ab: 1, "\x61b": 2}`, Options{},
+			Err{DebugCode: ErrDuplicateKey, Offset: 8, Line: 1, Column: 9, Path: "/ab"}},
+		{"duplicate_continued_key", "{ab: 1, 'a\\\nb': 2}", Options{},
+			Err{DebugCode: ErrDuplicateKey, Offset: 8, Line: 1, Column: 9, Path: "/ab"}},
+		{"unterminated_single_quoted", `['abc]`, Options{},
+			Err{DebugCode: ErrUnterminatedString, Offset: 1, Line: 1, Column: 2, Path: "/0"}},
+		{"mixed_quotes", `['abc"]`, Options{},
+			Err{DebugCode: ErrUnterminatedString, Offset: 1, Line: 1, Column: 2, Path: "/0"}},
+		{"newline_in_string", "['a\nb']", Options{},
+			Err{DebugCode: ErrControlCharInString, Offset: 3, Line: 1, Column: 4, Path: "/0"}},
+		{"newline_in_key", "{'a\nb': 1}", Options{},
+			Err{DebugCode: ErrControlCharInKey, Offset: 1, Line: 1, Column: 2}},
+		{"octal_escape", `'\01'`, Options{},
+			Err{DebugCode: ErrInvalidEscape, Offset: 0, Line: 1, Column: 1}},
+		{"digit_escape", `'\1'`, Options{},
+			Err{DebugCode: ErrInvalidEscape, Offset: 0, Line: 1, Column: 1}},
+		{"short_hex_escape", `'\x4'`, Options{},
+			Err{DebugCode: ErrInvalidHexEscape, Offset: 0, Line: 1, Column: 1}},
+		{"invalid_hex_escape", `'\xg0'`, Options{},
+			Err{DebugCode: ErrInvalidHexEscape, Offset: 0, Line: 1, Column: 1}},
+		{"short_unicode_escape", `'\u12'`, Options{},
+			Err{DebugCode: ErrShortUnicodeEscape, Offset: 0, Line: 1, Column: 1}},
+		{"hex_without_digits", `[0x]`, Options{},
+			Err{DebugCode: ErrExpectedHexDigit, Offset: 1, Line: 1, Column: 2, Path: "/0"}},
+		{"hex_invalid_digit", `0xg`, Options{},
+			Err{DebugCode: ErrExpectedHexDigit, Offset: 0, Line: 1, Column: 1}},
+		{"hex_fraction", `0x1.5`, Options{},
+			Err{DebugCode: ErrTrailingData, Offset: 3, Line: 1, Column: 4}},
+		{"lone_decimal_point", `[.]`, Options{},
+			Err{DebugCode: ErrExpectedFractionDigit, Offset: 1, Line: 1, Column: 2, Path: "/0"}},
+		{"decimal_point_exponent", `.e1`, Options{},
+			Err{DebugCode: ErrExpectedFractionDigit, Offset: 0, Line: 1, Column: 1}},
+		{"double_sign", `+-1`, Options{},
+			Err{DebugCode: ErrExpectedDigit, Offset: 0, Line: 1, Column: 1}},
+		{"lone_plus", `[+]`, Options{},
+			Err{DebugCode: ErrExpectedDigit, Offset: 1, Line: 1, Column: 2, Path: "/0"}},
+		{"leading_zero", `012`, Options{},
+			Err{DebugCode: ErrLeadingZero, Offset: 0, Line: 1, Column: 1}},
+		{"invalid_infinity", `[Infinty]`, Options{},
+			Err{DebugCode: ErrInvalidInfinity, Offset: 1, Line: 1, Column: 2, Path: "/0"}},
+		{"truncated_infinity", `-Inf`, Options{},
+			Err{DebugCode: ErrInvalidInfinity, Offset: 0, Line: 1, Column: 1}},
+		{"invalid_nan", `nan`, Options{},
+			Err{DebugCode: ErrInvalidNull, Offset: 0, Line: 1, Column: 1}},
+		{"truncated_nan", `[Na`, Options{},
+			Err{DebugCode: ErrInvalidNaN, Offset: 1, Line: 1, Column: 2, Path: "/0"}},
+		{"undefined", `undefined`, Options{},
+			Err{DebugCode: ErrExpectedValue, Offset: 0, Line: 1, Column: 1}},
+		{"double_trailing_comma", `[1,,]`, Options{},
+			Err{DebugCode: ErrExpectedValue, Offset: 3, Line: 1, Column: 4, Path: "/1"}},
+		{"unterminated_comment", `{a: 1 /*}`, Options{},
+			Err{DebugCode: ErrUnterminatedComment, Offset: 6, Line: 1, Column: 7, Path: "/a"}},
+		{"max_key_bytes_identifier", `{abcd: 1}`, Options{MaxKeyBytes: 3},
+			Err{DebugCode: ErrMaxKeyBytes, Offset: 4, Line: 1, Column: 5}},
+		{"max_key_bytes_escape", `{ab\u0063: 1}`, Options{MaxKeyBytes: 3},
+			Err{DebugCode: ErrMaxKeyBytes, Offset: 4, Line: 1, Column: 5}},
+		{"max_string_bytes", `'abcd'`, Options{MaxStringBytes: 3},
+			Err{DebugCode: ErrMaxStringBytes, Offset: 4, Line: 1, Column: 5}},
+		{"max_number_digits_hex", `0xabcd`, Options{MaxNumberDigits: 3},
+			Err{DebugCode: ErrMaxNumberDigits, Offset: 5, Line: 1, Column: 6}},
+		{"max_number_digits_fraction", `.1234`, Options{MaxNumberDigits: 3},
+			Err{DebugCode: ErrMaxNumberDigits, Offset: 4, Line: 1, Column: 5}},
+		{"ijson_infinity", `[Infinity]`, Options{Profile: IJSON},
+			Err{DebugCode: ErrNumberOutOfRange, Offset: 1, Line: 1, Column: 2, Path: "/0"}},
+		{"ijson_hex", `0x20000000000000`, Options{Profile: IJSON},
+			Err{DebugCode: ErrNumberOutOfRange, Offset: 0, Line: 1, Column: 1}},
+		{"ijson_duplicate_key", `{a: 1, a: 2}`, Options{Profile: IJSON},
+			Err{DebugCode: ErrDuplicateKey, Offset: 7, Line: 1, Column: 8, Path: "/a"}},
+		{"ijson_noncharacter_escaped", "['a\\\uFFFF']", Options{Profile: IJSON},
+			Err{DebugCode: ErrNoncharacter, Offset: 4, Line: 1, Column: 5, Path: "/0"}},
+		{"validate_utf8_identifier", "{a\xff: 1}", Options{ValidateUTF8: true},
+			Err{DebugCode: ErrExpectedColon, Offset: 2, Line: 1, Column: 3, Path: "/a"}},
+		{"validate_utf8_single_quoted", "'a\xff'", Options{ValidateUTF8: true},
+			Err{DebugCode: ErrInvalidUTF8, Offset: 2, Line: 1, Column: 3}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := tt.opts
+			opts.Dialect = JSON5
+			require.Equal(t, tt.expect, validateParts(t, opts, tt.in))
+		})
+	}
+}
+
+func TestJSON5IJSONHexInRange(t *testing.T) {
+	testProfile(t, Options{Dialect: JSON5, Profile: IJSON}, []profileCase{
+		{"hex in range", `[0x1fffffffffffff, -0x1fffffffffffff, +.5]`, 0, 0},
+	})
+}
+
+func TestDialectString(t *testing.T) {
+	require.Equal(t, "json", JSON.String())
+	require.Equal(t, "json5", JSON5.String())
+	require.Equal(t, "dialect (7)", Dialect(7).String())
+}
@@ -1,9 +1,12 @@
-package main
+// Package jsonvalidate provides a fast JSON validator
+package jsonvalidate
 
 import (
 	"fmt"
 	"strconv"
 	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
 	"unsafe"
 
 	"github.com/romshark/jsonvalidate-go/internal/stack"
@@ -11,22 +14,171 @@ import (
 
 // Err represents a parser error
 type Err struct {
-	DebugCode int
-	Offset    int
+	DebugCode ErrorCode
+
+	// Offset is the byte offset of the error in the input
+	Offset int
+
+	// Line and Column locate the error in the input counting from 1.
+	// Columns are counted in runes, see position for details
+	Line   int
+	Column int
+
+	// Path is the RFC 6901 JSON Pointer of the value
+	// the error was encountered in
+	Path string
+
+	// Cause is the error returned by the Handler
+	// in case of ErrHandlerAborted
+	Cause error
 }
 
 func (err Err) Error() string {
+	msg := err.DebugCode.String()
+	if err.Cause != nil {
+		msg += ": " + err.Cause.Error()
+	}
+	if err.Line < 1 {
+		return fmt.Sprintf(
+			"%s at offset %d",
+			msg,
+			err.Offset,
+		)
+	}
+	if err.Path == "" {
+		return fmt.Sprintf(
+			"%s at line %d, column %d (offset %d)",
+			msg,
+			err.Line,
+			err.Column,
+			err.Offset,
+		)
+	}
 	return fmt.Sprintf(
-		"error (%d) at offset %d",
-		err.DebugCode,
+		"%s at %s, line %d, column %d (offset %d)",
+		msg,
+		err.Path,
+		err.Line,
+		err.Column,
 		err.Offset,
 	)
 }
 
+// Unwrap returns the error returned by the Handler, if any,
+// and the error code otherwise
+func (err Err) Unwrap() error {
+	if err.Cause != nil {
+		return err.Cause
+	}
+	return err.DebugCode
+}
+
+// Is makes Err match its ErrorCode sentinel in errors.Is
+// even if it wraps the error returned by the Handler
+func (err Err) Is(target error) bool {
+	c, ok := target.(ErrorCode)
+	return ok && c == err.DebugCode
+}
+
 // Options defines validation options
 type Options struct {
 	ExpectDocument     bool
 	AllowDuplicateKeys bool
+
+	// MaxDepth limits the nesting depth of arrays and objects,
+	// the stack never grows beyond it. Zero means no limit
+	MaxDepth int
+
+	// MaxInputBytes limits the length of the input in bytes.
+	// Zero means no limit
+	MaxInputBytes int
+
+	// MaxStringBytes limits the length of string values
+	// in bytes as they appear in the input, excluding the quotes.
+	// Zero means no limit
+	MaxStringBytes int
+
+	// MaxKeyBytes limits the length of object keys
+	// in bytes as they appear in the input, excluding the quotes.
+	// Zero means no limit
+	MaxKeyBytes int
+
+	// MaxNumberDigits limits the number of digits of a number
+	// including the digits of the fraction and the exponent.
+	// Zero means no limit
+	MaxNumberDigits int
+
+	// MaxObjectKeys limits the number of fields of an object.
+	// Zero means no limit
+	MaxObjectKeys int
+
+	// MaxArrayElements limits the number of elements of an array.
+	// Zero means no limit
+	MaxArrayElements int
+
+	// MaxTotalValues limits the number of values in the input
+	// including the top-level value and all nested values.
+	// Zero means no limit
+	MaxTotalValues int
+
+	// ValidateUTF8 enables rejecting keys and string values
+	// that aren't valid UTF-8, including overlong encodings
+	// and encoded surrogates
+	ValidateUTF8 bool
+
+	// AllowControlChars disables rejecting unescaped control characters
+	// (U+0000 through U+001F) in string values, which RFC 8259 forbids.
+	// Object keys must never contain them
+	AllowControlChars bool
+
+	// ValidateSurrogates enables rejecting keys and string values
+	// with \u escape sequences encoding UTF-16 surrogates
+	// that aren't part of a high and low surrogate pair
+	ValidateSurrogates bool
+
+	// Profile selects the conformance rules, see Profile for details.
+	// AllowDuplicateKeys, AllowControlChars, AllowTrailingCommas,
+	// AllowComments and AllowNonFiniteNumbers are only honored
+	// by the Legacy profile
+	Profile Profile
+
+	// Dialect selects the grammar of the input, see Dialect for details.
+	// Honored by all profiles, the remaining rules of the profile
+	// apply to the JSON5 input
+	Dialect Dialect
+
+	// BOM selects how a byte order mark at the beginning
+	// of the input is treated, see BOMPolicy for details.
+	// ValidateNDJSON applies it to the first line only,
+	// the texts of ValidateJSONSeq following RS are never checked.
+	// Honored by all profiles
+	BOM BOMPolicy
+
+	// AllowTrailingCommas allows a ',' following the last field
+	// of an object or the last element of an array
+	AllowTrailingCommas bool
+
+	// AllowComments allows line (//) and block (/* */) comments
+	// anywhere whitespace is allowed
+	AllowComments bool
+
+	// AllowNonFiniteNumbers allows the literals NaN, Infinity
+	// and -Infinity wherever a value is allowed
+	AllowNonFiniteNumbers bool
+
+	// AllowMultipleValues allows the input to contain several
+	// top-level values in a row, optionally separated by whitespace,
+	// as read by json.Decoder. The input must still contain
+	// at least one value. Honored by all profiles
+	AllowMultipleValues bool
+
+	// MaxErrors limits the number of errors returned by ValidateAll.
+	// Zero means no limit
+	MaxErrors int
+
+	// SkipBlankLines makes ValidateNDJSON skip lines consisting
+	// of whitespace only instead of reporting them as empty input
+	SkipBlankLines bool
 }
 
 // Parser represents a JSON parser
@@ -54,233 +206,998 @@ func (pr *Parser) Validate(s string, opts Options) Err {
 	return pr.validate(s, opts)
 }
 
+// CheckBytes is similar to ValidateBytes but returns
+// nil if the value is valid and an Err otherwise
+func (pr *Parser) CheckBytes(s []byte, opts Options) error {
+	return pr.Check(b2s(s), opts)
+}
+
+// Check is similar to Validate but returns
+// nil if the value is valid and an Err otherwise
+func (pr *Parser) Check(s string, opts Options) error {
+	if err := pr.validate(s, opts); err.DebugCode != 0 {
+		return err
+	}
+	return nil
+}
+
 // validate validates the given document
 func (pr *Parser) validate(
 	input string,
 	opts Options,
-) (err Err) {
-	var (
-		containerType  stack.ContainerType
-		containerLevel int
-		elementIndex   int
-		sv             string
-		s              = input
-	)
-
-	stk := pr.stackPool.Acquire(
-		// Tell the stack to keep track of the keys
-		!opts.AllowDuplicateKeys,
-	)
-	defer pr.stackPool.Release(stk)
+) Err {
+	if err := checkInputLength(input, opts); err.DebugCode != 0 {
+		return err
+	}
 
-	currentOffset := func() int { return len(input) - len(s) }
-	error := func(debugCode int) Err {
-		return Err{
-			DebugCode: debugCode,
-			Offset:    currentOffset(),
+	var sc scanner
+	sc.init(pr.stackPool, opts, false)
+	defer pr.stackPool.Release(sc.stk)
+	if sc.strict(input) {
+		if sc.scanStrict(input) {
+			return Err{}
+		}
+		// Let the general scanner report the error
+		for sc.stk.Pop() {
 		}
 	}
+	_, err := sc.scan(input, true)
+	if err.DebugCode != 0 {
+		err.setPosition(input)
+	}
+	return err
+}
+
+// checkInputLength returns an error if the input
+// is longer than Options.MaxInputBytes
+func checkInputLength(input string, opts Options) Err {
+	if opts.MaxInputBytes < 1 || len(input) <= opts.MaxInputBytes {
+		return Err{}
+	}
+	err := Err{
+		DebugCode: ErrMaxInputBytes,
+		Offset:    opts.MaxInputBytes,
+	}
+	err.setPosition(input)
+	return err
+}
+
+// scanState defines what the scanner expects to find next
+type scanState byte
+
+const (
+	// stateValue expects a value
+	stateValue scanState = iota
+
+	// stateDocument expects the top-level object
+	// when Options.ExpectDocument is set
+	stateDocument
 
+	// stateFirstElement expects either the first array element or ']'
+	stateFirstElement
+
+	// stateFirstKey expects either the first object key or '}'
+	stateFirstKey
+
+	// stateKey expects an object key following ','
+	stateKey
+
+	// stateElement expects an array element following ','
+	stateElement
+
+	// stateColon expects the ':' following an object key
+	stateColon
+
+	// stateNext expects what may follow a complete value:
+	// ',' or the closing bracket of the current container,
+	// or the end of the input on the top level
+	stateNext
+)
+
+// scanner holds the state of a validation in progress.
+// Since the state doesn't live on the call stack the input
+// may be scanned in several consecutive parts
+type scanner struct {
+	opts  Options
+	rules rules
+	stk   *stack.Stack
+	state scanState
+
+	// offset is the offset of the next part of the input
+	// relative to the beginning of the input
+	offset int
+
+	// values is the number of values scanned so far
+	values int
+
+	// bom is set until the beginning of the input
+	// was checked for a byte order mark
+	bom bool
+
+	// h is called for each valid token unless nil
+	h Handler
+
+	// inString is set if scanning was suspended in a string,
+	// checked is the number of bytes following its opening quote
+	// that were validated already
+	inString bool
+	checked  int
+
+	// trackRanges enables collecting the byte ranges
+	// of the top-level values in ranges
+	trackRanges bool
+	ranges      []Range
+	valueStart  int
+}
+
+// init prepares the scanner for opts acquiring its stack from pool
+// which must be released. copyKeys is set if the keys must not
+// refer to the input
+func (sc *scanner) init(pool *stack.Pool, opts Options, copyKeys bool) {
+	*sc = scanner{opts: opts, rules: opts.rules(), bom: true}
+	// Tell the stack to keep track of the keys
+	sc.stk = pool.Acquire(!sc.rules.allowDuplicateKeys, copyKeys)
 	if opts.ExpectDocument {
-		// Scan object
-		s = skipWS(s)
-		if len(s) == 0 {
-			return error(1)
-		}
+		sc.state = stateDocument
+	}
+}
 
-		if s[0] != '{' {
-			return error(1)
+// scan scans the next part of the input.
+// Unless final is set, scanning stops at the beginning of a token
+// reaching the end of input since it might continue in the next part,
+// the number of bytes consumed is returned in this case.
+// In case of an error the number of bytes preceding
+// the failing token is returned
+func (sc *scanner) scan(input string, final bool) (int, Err) {
+	var (
+		sv    string
+		tail  string
+		value string // Unscanned tail beginning at the current value
+		code  ErrorCode
+		s     = input
+
+		// The hot part of the scanner state is kept in local variables
+		// and only written back when scanning is suspended
+		state     = sc.state
+		stk       = sc.stk
+		container = topContainer(stk)
+		h         = sc.h
+
+		// ext is set if any option requires checks per token,
+		// otherwise they're skipped testing this single flag
+		ext = sc.extended()
+	)
+
+	if sc.bom {
+		var done bool
+		s, code, done = sc.scanBOM(input, final)
+		if !done {
+			// The byte order mark might continue in the next part
+			return 0, Err{}
+		}
+		sc.bom = false
+		if code != 0 {
+			return sc.error(code, state, input, s)
 		}
-		stk.Push(stack.Object)
-		containerLevel++
-		s = s[1:]
 	}
 
-	// Check for premature EOF
-	s = skipWS(s)
-	if len(s) == 0 {
-		return error(67)
+	if sc.inString {
+		if n, err, done := sc.resumeString(input, final); done {
+			return n, err
+		}
 	}
 
-	// Scan elements
 	for {
 		s = skipWS(s)
+		if ext && sc.rules.allowComments && len(s) > 0 {
+			// Comments and the whitespace of JSON5
+			var (
+				n    int
+				err  Err
+				done bool
+			)
+			if s, n, err, done = sc.skipExtended(input, s, state, final); done {
+				return n, err
+			}
+		}
 		if len(s) == 0 {
-			if containerLevel > 0 {
-				return error(8)
+			sc.state = state
+			if final {
+				if code = sc.eofCode(); code != 0 {
+					return sc.error(code, state, input, s)
+				}
 			}
-			return
+			return len(input), Err{}
 		}
 
-		containerType, elementIndex, containerLevel = stk.Top()
-
-		switch containerType {
-		case stack.Object:
-			// In object
-			switch {
-			case s[0] == '}':
-				// Object termination
-				if !stk.Pop() {
-					// No container to terminate
-					return error(7)
+		switch state {
+		case stateNext:
+			switch container {
+			case stack.Object:
+				// In object
+				switch s[0] {
+				case '}':
+					// Object termination
+					stk.Pop()
+					container = topContainer(stk)
+					if ext {
+						if n, err := sc.endContainer(input, s, container); err.DebugCode != 0 {
+							return n, err
+						}
+					}
+				case ',':
+					// Subsequent object field
+					state = stateKey
+				default:
+					return sc.error(ErrExpectedCommaOrBrace, state, input, s)
+				}
+			case stack.Array:
+				// In array
+				switch s[0] {
+				case ']':
+					// Array termination
+					stk.Pop()
+					container = topContainer(stk)
+					if ext {
+						if n, err := sc.endContainer(input, s, container); err.DebugCode != 0 {
+							return n, err
+						}
+					}
+				case ',':
+					// Subsequent array element
+					trailing := false
+					if ext && sc.rules.allowTrailingCommas && sc.elementsFull() {
+						// Only a trailing comma may follow the last element
+						t := skipSpace(s[1:], sc.rules.allowComments, sc.rules.json5)
+						if !final && (len(t) == 0 || t[0] == '/' || !utf8.FullRuneInString(t)) {
+							// The next token might be in the next part
+							sc.state = state
+							return len(input) - len(s), Err{}
+						}
+						trailing = len(t) > 0 && t[0] == ']'
+					}
+					stk.PushElement()
+					if ext && sc.elementsExceeded() && !trailing {
+						// Report the element beyond the limit
+						return sc.error(ErrMaxArrayElements, stateValue, input, s)
+					}
+					state = stateElement
+				default:
+					return sc.error(ErrExpectedCommaOrBracket, state, input, s)
+				}
+			default:
+				// Void, nothing may follow the top-level value
+				// except for another one
+				if !sc.opts.AllowMultipleValues {
+					return sc.error(ErrTrailingData, state, input, s)
+				}
+				state = stateValue
+				if sc.opts.ExpectDocument {
+					state = stateDocument
 				}
-				_, _, containerLevel = stk.Top()
-				s = s[1:]
 				continue
-			case elementIndex > 0:
-				// Parse subsequent object element value
-				if s[0] != ',' {
-					return error(16)
+			}
+			s = s[1:]
+			continue
+
+		case stateFirstKey, stateKey:
+			if s[0] == '}' &&
+				(state == stateFirstKey || sc.rules.allowTrailingCommas) {
+				// Empty object or trailing comma termination
+				stk.Pop()
+				container = topContainer(stk)
+				if ext {
+					if n, err := sc.endContainer(input, s, container); err.DebugCode != 0 {
+						return n, err
+					}
 				}
+				state = stateNext
 				s = s[1:]
+				continue
 			}
 
 			// Scan field name
-			s = skipWS(s)
-			if len(s) == 0 || s[0] != '"' {
+			q := 1 // Length of the opening quote
+			switch {
+			case ext && sc.rules.json5:
+				sv, tail, q, code = scanKey5(s, sc.opts.MaxKeyBytes)
+				if !final && q == 0 && !utf8.FullRuneInString(tail) {
+					// The identifier might continue in the next part
+					sc.state = state
+					return len(input) - len(s), Err{}
+				}
+			case s[0] != '"':
 				// Unexpected token, expected field initializer
-				return error(21)
+				return sc.error(ErrExpectedKey, state, input, s)
+			default:
+				sv, tail, code = scanKey(s[1:], sc.opts.MaxKeyBytes)
 			}
-
-			err.Offset = currentOffset()
-			sv, s, err.DebugCode = scanKey(s[1:])
-			if err.DebugCode != 0 {
-				return
+			if code != 0 {
+				if q == 1 && (code == ErrUnterminatedString || code == ErrMaxKeyBytes) {
+					// Report invalid bytes preceding the missing end
+					next, n, err := sc.checkOpenString(input, s, code, sc.opts.MaxKeyBytes, state, 0)
+					if err.DebugCode != 0 {
+						return n, err
+					}
+					if !final && code == ErrUnterminatedString {
+						sc.inString, sc.checked = true, next
+						sc.state = state
+						return len(input) - len(s), Err{}
+					}
+				}
+				if code == ErrMaxKeyBytes {
+					// Report the first byte beyond the limit
+					return sc.errorAt(code, state, input, s, tail)
+				}
+				return sc.error(code, state, input, s)
 			}
 			// Check key length
-			if len(sv) < 1 {
-				err.DebugCode = 78
-				return
+			if len(sv) < 1 && !sc.rules.allowEmptyKeys {
+				return sc.error(ErrEmptyKey, state, input, s)
 			}
-
-			// Scan the key for control chars.
-			for i := 0; i < len(sv); i++ {
-				if sv[i] < 0x20 {
-					err.DebugCode = 29
-					return
+			if ext {
+				if i, code := sc.checkKey(sv); code != 0 {
+					if code == ErrMaxObjectKeys {
+						// The key beyond the limit isn't tracked
+						return sc.error(code, state, input, s)
+					}
+					return sc.errorAt(code, state, input, s, s[q+i:])
 				}
 			}
 
-			if !opts.AllowDuplicateKeys {
-				// Check for duplicate keys
-				if !stk.PushField(sv) {
-					err.DebugCode = 91
-					return
+			// Check for duplicate keys unless they're allowed.
+			// Keys are compared by their decoded value,
+			// only keys containing escape sequences need to be decoded
+			if !sc.rules.allowDuplicateKeys && strings.IndexByte(sv, '\\') >= 0 {
+				sv = unescapeKey(sv)
+			}
+			if !stk.PushField(sv) {
+				// Report the duplicate key as part of the path
+				return sc.error(ErrDuplicateKey, stateColon, input, s)
+			}
+			if h != nil {
+				if n, err := sc.handle(input, s, len(s)-len(tail), true); err.DebugCode != 0 {
+					return n, err
 				}
-			} else {
-				// Ignore duplicate keys
-				stk.PushElement()
 			}
+			state = stateColon
+			s = tail
+			continue
 
+		case stateColon:
 			// Scan ':'
-			s = skipWS(s)
-			if len(s) == 0 {
-				// Unexpected EOF
-				return error(13)
-			}
 			if s[0] != ':' {
 				// Unexpected token
-				return error(5)
+				return sc.error(ErrExpectedColon, state, input, s)
 			}
+			state = stateValue
 			s = s[1:]
+			continue
 
-			// Scan field value
-
-		case stack.Array:
-			// In array
-			switch {
-			case s[0] == ']':
-				// Array termination
-				if !stk.Pop() {
-					// No container to terminate
-					return error(14)
+		case stateFirstElement:
+			if s[0] == ']' {
+				// Empty array termination
+				stk.Pop()
+				container = topContainer(stk)
+				if ext {
+					if n, err := sc.endContainer(input, s, container); err.DebugCode != 0 {
+						return n, err
+					}
 				}
-				_, _, containerLevel = stk.Top()
+				state = stateNext
 				s = s[1:]
 				continue
-			case elementIndex > 0:
-				// Parse subsequent array element value
-				if s[0] != ',' {
-					return error(15)
-				}
-				s = s[1:]
 			}
-
 			// Push a new element onto the current stack object
 			stk.PushElement()
 
-		default:
-			// Void
-			if s[0] == ',' {
-				// Unexpected element separator
-				return error(61)
+		case stateElement:
+			if s[0] == ']' && sc.rules.allowTrailingCommas {
+				// Trailing comma termination
+				stk.Pop()
+				container = topContainer(stk)
+				if ext {
+					if n, err := sc.endContainer(input, s, container); err.DebugCode != 0 {
+						return n, err
+					}
+				}
+				state = stateNext
+				s = s[1:]
+				continue
+			}
+
+		case stateDocument:
+			if s[0] != '{' {
+				return sc.error(ErrExpectedObject, state, input, s)
 			}
 		}
 
 		// Parse value
-		s = skipWS(s)
-		if len(s) == 0 {
-			return error(50)
+		if ext {
+			if sc.opts.MaxTotalValues > 0 && sc.values >= sc.opts.MaxTotalValues {
+				return sc.error(ErrMaxTotalValues, state, input, s)
+			}
+			if sc.trackRanges && container == stack.Void {
+				sc.valueStart = sc.offset + len(input) - len(s)
+			}
 		}
-
+		state = stateNext
+		value = s
 		switch s[0] {
 		case '"':
 			// String value
-			err.Offset = currentOffset()
-			_, s, err.DebugCode = scanString(s[1:])
-			if err.DebugCode != 0 {
-				return
+			tail, n, err, done := sc.scanStringValue(input, s, final)
+			if done {
+				return n, err
 			}
-			continue
+			s = tail
 
 		case 'n':
 			// Null
 			if len(s) < len("null") || s[:len("null")] != "null" {
-				return error(24)
+				if !final && strings.HasPrefix("null", s) {
+					sc.state = stateValue
+					return len(input) - len(s), Err{}
+				}
+				return sc.error(ErrInvalidNull, state, input, s)
 			}
 			s = s[len("null"):]
-			continue
 
 		case '[':
 			// Array
+			if sc.depthExceeded() {
+				return sc.error(ErrMaxDepth, state, input, s)
+			}
 			stk.Push(stack.Array)
+			container = stack.Array
+			state = stateFirstElement
 			s = s[1:]
-			continue
 
 		case '{':
 			// Object
+			if sc.depthExceeded() {
+				return sc.error(ErrMaxDepth, state, input, s)
+			}
 			stk.Push(stack.Object)
+			container = stack.Object
+			state = stateFirstKey
 			s = s[1:]
-			continue
 
 		case 't':
 			// Boolean (true)
 			if len(s) < len("true") || s[:len("true")] != "true" {
-				return error(22)
+				if !final && strings.HasPrefix("true", s) {
+					sc.state = stateValue
+					return len(input) - len(s), Err{}
+				}
+				return sc.error(ErrInvalidTrue, state, input, s)
 			}
 			s = s[len("true"):]
-			continue
 
 		case 'f':
 			// Boolean (false)
 			if len(s) < len("false") || s[:len("false")] != "false" {
-				return error(23)
+				if !final && strings.HasPrefix("false", s) {
+					sc.state = stateValue
+					return len(input) - len(s), Err{}
+				}
+				return sc.error(ErrInvalidFalse, state, input, s)
 			}
 			s = s[len("false"):]
+
+		case '-', '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
+			// Number
+			if ext && sc.rules.json5 {
+				tail, n, err, done := sc.scanScalar5(input, s, final)
+				if done {
+					return n, err
+				}
+				s = tail
+				break
+			}
+			if ext && sc.rules.allowNonFinite && len(s) > 1 && s[1] == 'I' {
+				tail, n, err, done := sc.scanNonFinite(
+					input, s, "-Infinity", ErrInvalidInfinity, final,
+				)
+				if done {
+					return n, err
+				}
+				s = tail
+				break
+			}
+			tail, code = scanNumber(s, sc.opts.MaxNumberDigits)
+			if !final && len(tail) == 0 {
+				// The number might continue in the next part
+				sc.state = stateValue
+				return len(input) - len(s), Err{}
+			}
+			if code == ErrMaxNumberDigits {
+				// Report the first digit beyond the limit
+				return sc.errorAt(code, state, input, s, tail)
+			}
+			if code != 0 {
+				return sc.error(code, state, input, s)
+			}
+			if ext && sc.rules.validateNumbers && !isDoubleNumber(s[:len(s)-len(tail)]) {
+				return sc.error(ErrNumberOutOfRange, state, input, s)
+			}
+			s = tail
+
+		default:
+			if ext && sc.rules.json5 {
+				// Single-quoted string, number or Infinity and NaN
+				tail, n, err, done := sc.scanScalar5(input, s, final)
+				if done {
+					return n, err
+				}
+				s = tail
+				break
+			}
+			if ext && sc.rules.allowNonFinite && (s[0] == 'I' || s[0] == 'N') {
+				literal, code := "Infinity", ErrInvalidInfinity
+				if s[0] == 'N' {
+					literal, code = "NaN", ErrInvalidNaN
+				}
+				tail, n, err, done := sc.scanNonFinite(input, s, literal, code, final)
+				if done {
+					return n, err
+				}
+				s = tail
+				break
+			}
+			return sc.error(ErrExpectedValue, state, input, s)
+		}
+		if ext {
+			// Values are counted once complete since
+			// incomplete ones are scanned again in the next part
+			sc.values++
+			if container == stack.Void {
+				// Top-level scalar
+				sc.endValue(input, s)
+			}
+			if h != nil {
+				if n, err := sc.handle(input, value, len(value)-len(s), false); err.DebugCode != 0 {
+					return n, err
+				}
+			}
+		}
+	}
+}
+
+// extended returns true if any of the options requires
+// checks per token beyond the grammar and the limits
+// checked by the strict path
+func (sc *scanner) extended() bool {
+	return sc.h != nil || sc.trackRanges ||
+		sc.rules.allowComments || sc.rules.json5 || sc.rules.allowNonFinite ||
+		sc.rules.allowTrailingCommas ||
+		sc.rules.validateUTF8 || sc.rules.validateSurrogates ||
+		sc.rules.rejectNoncharacters || sc.rules.validateNumbers ||
+		sc.opts.MaxTotalValues > 0 ||
+		sc.opts.MaxObjectKeys > 0 ||
+		sc.opts.MaxArrayElements > 0
+}
+
+// strict returns true if the complete input can be validated
+// by scanStrict, which is the case if no option requires checks
+// beyond the grammar, the duplicate keys and the lengths
+func (sc *scanner) strict(input string) bool {
+	return !sc.extended() &&
+		!sc.opts.ExpectDocument && !sc.opts.AllowMultipleValues &&
+		sc.opts.BOM != Require && !strings.HasPrefix(input, bom)
+}
+
+// scanStrict scans the complete input on the strict path
+// without keeping track of the scanner state.
+// It returns false if the input is invalid
+// in which case scan must be used to locate the error
+func (sc *scanner) scanStrict(input string) bool {
+	var (
+		stk  = sc.stk
+		s    = input
+		code ErrorCode
+	)
+	for {
+		// Parse value
+		s = skipWS(s)
+		if len(s) == 0 {
+			return false
+		}
+		switch s[0] {
+		case '"':
+			// String value
+			_, s, code = scanString(s[1:], sc.opts.MaxStringBytes)
+			if code != 0 {
+				return false
+			}
+
+		case 'n':
+			// Null
+			if !strings.HasPrefix(s, "null") {
+				return false
+			}
+			s = s[len("null"):]
+
+		case '[':
+			// Array
+			if sc.depthExceeded() {
+				return false
+			}
+			stk.Push(stack.Array)
+			if s = skipWS(s[1:]); len(s) == 0 || s[0] != ']' {
+				// Push the first element onto the array
+				stk.PushElement()
+				continue
+			}
+			// Empty array termination
+			stk.Pop()
+			s = s[1:]
+
+		case '{':
+			// Object
+			if sc.depthExceeded() {
+				return false
+			}
+			stk.Push(stack.Object)
+			if s = skipWS(s[1:]); len(s) > 0 && s[0] == '}' {
+				// Empty object termination
+				stk.Pop()
+				s = s[1:]
+				break
+			}
+			if s = sc.scanStrictKey(s); len(s) == 0 {
+				return false
+			}
 			continue
 
+		case 't':
+			// Boolean (true)
+			if !strings.HasPrefix(s, "true") {
+				return false
+			}
+			s = s[len("true"):]
+
+		case 'f':
+			// Boolean (false)
+			if !strings.HasPrefix(s, "false") {
+				return false
+			}
+			s = s[len("false"):]
+
 		case '-', '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
 			// Number
-			err.Offset = currentOffset()
-			s, err.DebugCode = scanNumber(s)
-			if err.DebugCode != 0 {
-				return
+			s, code = scanNumber(s, sc.opts.MaxNumberDigits)
+			if code != 0 {
+				return false
 			}
 
 		default:
-			return error(20)
+			return false
+		}
+
+		// Scan the terminations up to the next value
+		for {
+			s = skipWS(s)
+			container := topContainer(stk)
+			if container == stack.Void {
+				// Nothing may follow the top-level value
+				return len(s) == 0
+			}
+			if len(s) == 0 {
+				return false
+			}
+			if s[0] == ']' && container == stack.Array ||
+				s[0] == '}' && container == stack.Object {
+				stk.Pop()
+				s = s[1:]
+				continue
+			}
+			if s[0] != ',' {
+				return false
+			}
+			if container == stack.Array {
+				// Subsequent array element
+				stk.PushElement()
+				s = s[1:]
+			} else if s = sc.scanStrictKey(skipWS(s[1:])); len(s) == 0 {
+				return false
+			}
+			break
+		}
+	}
+}
+
+// scanStrictKey scans the key and the colon at the beginning of s
+// for scanStrict. The returned tail is empty if they're invalid
+func (sc *scanner) scanStrictKey(s string) string {
+	if len(s) == 0 || s[0] != '"' {
+		return ""
+	}
+	sv, tail, code := scanKey(s[1:], sc.opts.MaxKeyBytes)
+	if code != 0 || len(sv) < 1 && !sc.rules.allowEmptyKeys {
+		return ""
+	}
+	if !sc.rules.allowDuplicateKeys && strings.IndexByte(sv, '\\') >= 0 {
+		sv = unescapeKey(sv)
+	}
+	if !sc.stk.PushField(sv) {
+		return ""
+	}
+	if tail = skipWS(tail); len(tail) == 0 || tail[0] != ':' {
+		return ""
+	}
+	return tail[1:]
+}
+
+// endContainer records the end of the container of type container
+// terminated at the beginning of s which is the unscanned tail of input.
+// container is the type of the container enclosing it
+func (sc *scanner) endContainer(input, s string, container stack.ContainerType) (int, Err) {
+	if container == stack.Void {
+		sc.endValue(input, s[1:])
+	}
+	if sc.h != nil {
+		return sc.handle(input, s, 1, false)
+	}
+	return 0, Err{}
+}
+
+// checkKey checks the key sv against the profile and the limits.
+// i is the index of the invalid byte in sv
+// unless code is ErrMaxObjectKeys
+func (sc *scanner) checkKey(sv string) (i int, code ErrorCode) {
+	if i, code = sc.checkString(sv); code != 0 {
+		return i, code
+	}
+	if sc.keysExceeded() {
+		return 0, ErrMaxObjectKeys
+	}
+	return 0, 0
+}
+
+// checkString checks the encoding of the string sv against the profile,
+// i is the index of the invalid byte in sv
+func (sc *scanner) checkString(sv string) (i int, code ErrorCode) {
+	if sc.rules.validateUTF8 {
+		if i := invalidUTF8(sv); i >= 0 {
+			return i, ErrInvalidUTF8
+		}
+	}
+	if sc.rules.validateSurrogates {
+		if i, code := loneSurrogate(sv); code != 0 {
+			return i, code
 		}
 	}
+	if sc.rules.rejectNoncharacters {
+		if i := noncharacter(sv); i >= 0 {
+			return i, ErrNoncharacter
+		}
+	}
+	return 0, 0
+}
+
+// scanNonFinite scans the non-finite number literal
+// at the beginning of s which is the unscanned tail of input.
+// done is true if scanning is suspended or failed,
+// in which case n and err are the results of scan
+func (sc *scanner) scanNonFinite(
+	input, s, literal string,
+	code ErrorCode,
+	final bool,
+) (tail string, n int, err Err, done bool) {
+	if !strings.HasPrefix(s, literal) {
+		if !final && strings.HasPrefix(literal, s) {
+			sc.state = stateValue
+			return "", len(input) - len(s), Err{}, true
+		}
+		n, err = sc.error(code, stateNext, input, s)
+		return "", n, err, true
+	}
+	return s[len(literal):], 0, Err{}, false
+}
+
+// scanStringValue scans the string value at the beginning of s
+// which is the unscanned tail of input and returns the tail following it.
+// If scanning is suspended or failed done is set
+// and n and err are the results of scan
+func (sc *scanner) scanStringValue(
+	input, s string,
+	final bool,
+) (tail string, n int, err Err, done bool) {
+	var (
+		sv   string
+		code ErrorCode
+
+		scanEsc = scanEscape
+		ctl     = controlChar
+	)
+	if sc.rules.json5 {
+		sv, tail, code = scanString5(s[1:], s[0], sc.opts.MaxStringBytes)
+		scanEsc, ctl = scanEscape5, lineTerminator
+	} else {
+		sv, tail, code = scanString(s[1:], sc.opts.MaxStringBytes)
+	}
+	if code == ErrControlCharInString && sc.rules.allowControlChars {
+		code = invalidEscape(sv, scanEsc)
+	}
+	if code == ErrUnterminatedString || code == ErrMaxStringBytes {
+		// Report invalid bytes preceding the missing end
+		var next int
+		next, n, err = sc.checkOpenString(input, s, code, sc.opts.MaxStringBytes, stateNext, 0)
+		if err.DebugCode != 0 {
+			return "", n, err, true
+		}
+		if !final && code == ErrUnterminatedString {
+			sc.inString, sc.checked = true, next
+			sc.state = stateValue
+			return "", len(input) - len(s), Err{}, true
+		}
+	}
+	switch code {
+	case 0:
+		if i, code := sc.checkString(sv); code != 0 {
+			n, err = sc.errorAt(code, stateNext, input, s, s[1+i:])
+			return "", n, err, true
+		}
+		return tail, 0, Err{}, false
+	case ErrMaxStringBytes:
+		// Report the first byte beyond the limit
+		n, err = sc.errorAt(code, stateNext, input, s, tail)
+	case ErrControlCharInString:
+		n, err = sc.errorAt(code, stateNext, input, s, s[1+ctl(sv):])
+	default:
+		n, err = sc.error(code, stateNext, input, s)
+	}
+	return "", n, err, true
+}
+
+// skipExtended skips the comments, and in JSON5 the whitespace,
+// at the beginning of s which is the unscanned tail of input
+// scanned in state. done is true if scanning is suspended or failed,
+// in which case n and err are the results of scan
+func (sc *scanner) skipExtended(
+	input, s string,
+	state scanState,
+	final bool,
+) (tail string, n int, err Err, done bool) {
+	for {
+		s = skipComments(s)
+		if sc.rules.json5 && len(s) > 0 && (s[0] >= utf8.RuneSelf || s[0] == '\v' || s[0] == '\f') {
+			if t := skipWS5(s); len(t) < len(s) {
+				s = t
+				continue
+			}
+			if !final && !utf8.FullRuneInString(s) {
+				// The rune might be whitespace
+				sc.state = state
+				return "", len(input) - len(s), Err{}, true
+			}
+		}
+		if len(s) > 0 && s[0] == '/' {
+			switch {
+			case len(s) > 1 && s[1] == '*':
+				if !final {
+					// The comment might end in the next part
+					sc.state = state
+					return "", len(input) - len(s), Err{}, true
+				}
+				n, err = sc.error(ErrUnterminatedComment, state, input, s)
+				return "", n, err, true
+			case len(s) == 1 || s[1] == '/':
+				if !final {
+					// The comment might continue in the next part
+					sc.state = state
+					return "", len(input) - len(s), Err{}, true
+				}
+				if len(s) > 1 {
+					// The line comment ends with the input
+					s = s[len(s):]
+				}
+			}
+		}
+		return s, 0, Err{}, false
+	}
+}
+
+// endValue records the end of the top-level value
+// preceding s which is the unscanned tail of input
+func (sc *scanner) endValue(input, s string) {
+	if sc.trackRanges {
+		sc.ranges = append(sc.ranges, Range{
+			Start: sc.valueStart,
+			End:   sc.offset + len(input) - len(s),
+		})
+	}
+}
+
+// error returns an error at the beginning of s
+// which is the unscanned tail of input, state is the state
+// the scanner was in when the error was encountered
+func (sc *scanner) error(
+	debugCode ErrorCode,
+	state scanState,
+	input, s string,
+) (int, Err) {
+	return sc.errorAt(debugCode, state, input, s, s)
+}
+
+// errorAt is similar to error but returns an error at the beginning
+// of at which is a tail of the failing token beginning at token.
+// The number of bytes preceding the token is returned
+func (sc *scanner) errorAt(
+	debugCode ErrorCode,
+	state scanState,
+	input, token, at string,
+) (int, Err) {
+	sc.state = state
+	return len(input) - len(token), Err{
+		DebugCode: debugCode,
+		Offset:    sc.offset + len(input) - len(at),
+		Path:      sc.pointer(),
+	}
+}
+
+// topContainer returns the type of the current container
+func topContainer(stk *stack.Stack) stack.ContainerType {
+	containerType, _, _ := stk.Top()
+	return containerType
+}
+
+// depthExceeded returns true if pushing another container
+// would exceed Options.MaxDepth
+func (sc *scanner) depthExceeded() bool {
+	if sc.opts.MaxDepth < 1 {
+		return false
+	}
+	_, _, containerLevel := sc.stk.Top()
+	return containerLevel >= sc.opts.MaxDepth
+}
+
+// keysExceeded returns true if pushing another field onto
+// the current object would exceed Options.MaxObjectKeys
+func (sc *scanner) keysExceeded() bool {
+	if sc.opts.MaxObjectKeys < 1 {
+		return false
+	}
+	_, numElements, _ := sc.stk.Top()
+	return numElements >= sc.opts.MaxObjectKeys
+}
+
+// elementsExceeded returns true if the current array
+// has more elements than Options.MaxArrayElements
+func (sc *scanner) elementsExceeded() bool {
+	if sc.opts.MaxArrayElements < 1 {
+		return false
+	}
+	_, numElements, _ := sc.stk.Top()
+	return numElements > sc.opts.MaxArrayElements
+}
+
+// elementsFull returns true if pushing another element onto
+// the current array would exceed Options.MaxArrayElements
+func (sc *scanner) elementsFull() bool {
+	if sc.opts.MaxArrayElements < 1 {
+		return false
+	}
+	_, numElements, _ := sc.stk.Top()
+	return numElements >= sc.opts.MaxArrayElements
+}
+
+// eofCode returns the error code for the input ending in the current state,
+// or 0 if the input is allowed to end
+func (sc *scanner) eofCode() ErrorCode {
+	_, _, containerLevel := sc.stk.Top()
+	switch sc.state {
+	case stateDocument, stateValue:
+		if containerLevel < 1 {
+			return ErrEmptyInput
+		}
+	case stateNext:
+		if containerLevel < 1 {
+			return 0
+		}
+	}
+	return ErrUnexpectedEOF
 }
 
 func skipWS(s string) string {
@@ -303,9 +1220,61 @@ func skipWSSlow(s string) string {
 	return ""
 }
 
+// skipSpace is similar to skipWS but also skips comments
+// if comments is set and the whitespace of JSON5 if json5 is set
+func skipSpace(s string, comments, json5 bool) string {
+	for {
+		s = skipWS(s)
+		if comments {
+			s = skipComments(s)
+		}
+		if !json5 {
+			return s
+		}
+		t := skipWS5(s)
+		if len(t) == len(s) {
+			return s
+		}
+		s = t
+	}
+}
+
+// skipComments skips whitespace and comments.
+// It stops at the beginning of a comment that isn't terminated
+func skipComments(s string) string {
+	for {
+		s = skipWS(s)
+		if len(s) < 2 || s[0] != '/' {
+			return s
+		}
+		switch s[1] {
+		case '/':
+			// Line comment
+			i := strings.IndexByte(s[2:], '\n')
+			if i < 0 {
+				return s
+			}
+			s = s[2+i+1:]
+		case '*':
+			// Block comment
+			i := strings.Index(s[2:], "*/")
+			if i < 0 {
+				return s
+			}
+			s = s[2+i+2:]
+		default:
+			return s
+		}
+	}
+}
+
 // scanKey is similar to scanString, but is optimized
 // for typical object keys, which are quite small and have no escape sequences.
-func scanKey(s string) (string, string, int) {
+// Keys must not contain control characters.
+func scanKey(s string, maxLen int) (string, string, ErrorCode) {
+	if maxLen > 0 && len(s) > maxLen {
+		return scanLimited(s, maxLen, scanKey, ErrMaxKeyBytes)
+	}
 	for i := 0; i < len(s); i++ {
 		if s[i] == '"' {
 			// Fast path - the key doesn't contain escape sequences.
@@ -313,68 +1282,333 @@ func scanKey(s string) (string, string, int) {
 		}
 		if s[i] == '\\' {
 			// Slow path - the key contains escape sequences.
-			return scanString(s)
+			sv, tail, errCode := scanString(s, 0)
+			if errCode == ErrControlCharInString {
+				return sv, tail, ErrControlCharInKey
+			}
+			return sv, tail, errCode
+		}
+		if s[i] < 0x20 {
+			// Control character
+			return "", s, ErrControlCharInKey
 		}
 	}
 	// Missing closing "
-	return "", s, 800
+	return "", s, ErrUnterminatedString
 }
 
-func scanString(s string) (string, string, int) {
-	// Try fast path - a string without escape sequences.
-	if n := strings.IndexByte(s, '"'); n >= 0 && strings.IndexByte(s[:n], '\\') < 0 {
+// scanString scans the string at the beginning of s returning
+// the raw string and the tail following it. The first invalid escape
+// sequence or control character is reported. A string containing
+// a control character is scanned completely and returned with
+// ErrControlCharInString, see invalidEscape for checking the rest of it
+func scanString(s string, maxLen int) (string, string, ErrorCode) {
+	if maxLen > 0 && len(s) > maxLen {
+		return scanLimited(s, maxLen, scanString, ErrMaxStringBytes)
+	}
+
+	// Try fast path - a string without escape sequences
+	// and control characters.
+	if n := strings.IndexByte(s, '"'); n >= 0 && isPlain(s[:n]) {
 		return s[:n], s[n+1:], 0
 	}
 
-	// Slow path - escape sequences are present.
-	rs, tail, errCode := scanRawString(s)
+	// Slow path - escape sequences or control characters are present.
+	raw, tail, errCode := scanRawString(s)
 	if errCode != 0 {
-		return rs, tail, errCode
+		return raw, tail, errCode
 	}
+	rs := raw
 	for {
 		n := strings.IndexByte(rs, '\\')
 		if n < 0 {
-			return rs, tail, 0
+			n = len(rs)
 		}
-		n++
-		if n >= len(rs) {
-			return rs, tail, 500
+		if controlChar(rs[:n]) >= 0 {
+			return raw, tail, ErrControlCharInString
 		}
-		ch := rs[n]
-		rs = rs[n+1:]
-		switch ch {
-		case '"', '\\', '/', 'b', 'f', 'n', 'r', 't':
-			// Valid escape sequences - see http://json.org/
-			break
-		case 'u':
-			if len(rs) < 4 {
-				// Escape sequence too short
-				return rs, tail, 400
-			}
-			xs := rs[:4]
-			_, err := strconv.ParseUint(xs, 16, 16)
-			if err != nil {
-				// Invalid escape sequence
-				return rs, tail, 401
-			}
-			rs = rs[4:]
+		if n == len(rs) {
+			return raw, tail, 0
+		}
+		// The raw string can't end with an unescaped backslash
+		l, code := scanEscape(rs[n+1:])
+		if code != 0 {
+			return raw, tail, code
+		}
+		rs = rs[n+1+l:]
+	}
+}
+
+// scanEscape scans the escape sequence following a backslash
+// at the beginning of the non-empty s returning its length
+func scanEscape(s string) (int, ErrorCode) {
+	switch s[0] {
+	case '"', '\\', '/', 'b', 'f', 'n', 'r', 't':
+		// Valid escape sequences - see http://json.org/
+		return 1, 0
+	case 'u':
+		if len(s) < 5 {
+			// Escape sequence too short
+			return 0, ErrShortUnicodeEscape
+		}
+		if _, err := strconv.ParseUint(s[1:5], 16, 16); err != nil {
+			// Invalid escape sequence
+			return 0, ErrInvalidUnicodeEscape
+		}
+		return 5, 0
+	}
+	// Unknown escape sequence
+	return 0, ErrInvalidEscape
+}
+
+// escapeLen returns the number of bytes following a backslash
+// needed to tell whether the escape sequence beginning with c is valid
+func escapeLen(c byte) int {
+	if c == 'u' {
+		return 5
+	}
+	return 1
+}
+
+// invalidEscape returns the code of the first invalid escape sequence
+// of the raw string s, which may contain control characters,
+// scanning escape sequences with scan
+func invalidEscape(s string, scan func(string) (int, ErrorCode)) ErrorCode {
+	for i := strings.IndexByte(s, '\\'); i >= 0; i = strings.IndexByte(s, '\\') {
+		n, code := scan(s[i+1:])
+		if code != 0 {
+			return code
+		}
+		s = s[i+1+n:]
+	}
+	return 0
+}
+
+// checkOpenString validates the string at the beginning of s, which is
+// the unscanned tail of input, that is unterminated or, unless code is
+// ErrUnterminatedString, exceeds maxLen. Its bytes following the opening
+// quote are validated from the index from up to the end of input or
+// the limit, excluding an escape sequence that might be cut off,
+// and the index validation stopped at is returned.
+// Errors are reported in state which is stateNext unless it's a key.
+// If the string is invalid n and err are the results of scan
+func (sc *scanner) checkOpenString(
+	input, s string,
+	code ErrorCode,
+	maxLen int,
+	state scanState,
+	from int,
+) (next, n int, err Err) {
+	raw := s[1:]
+	if code != ErrUnterminatedString {
+		raw = raw[:maxLen]
+	}
+	scanEsc, escLen := scanEscape, escapeLen
+	if sc.rules.json5 {
+		scanEsc, escLen = scanEscape5, escapeLen5
+	}
+	key := state != stateNext
+	i := from
+	for i < len(raw) {
+		c := raw[i]
+		if c == '\\' {
+			e := raw[i+1:]
+			if len(e) == 0 || len(e) < escLen(e[0]) {
+				// The escape sequence might be cut off
+				break
+			}
+			l, code := scanEsc(e)
+			if code != 0 {
+				n, err = sc.error(code, state, input, s)
+				return i, n, err
+			}
+			i += 1 + l
+			continue
+		}
+		ctl := c == '\n' || c == '\r' || c < 0x20 && !sc.rules.json5
+		if ctl && key {
+			n, err = sc.error(ErrControlCharInKey, state, input, s)
+			return i, n, err
+		}
+		if ctl && !sc.rules.allowControlChars {
+			n, err = sc.errorAt(ErrControlCharInString, state, input, s, s[1+i:])
+			return i, n, err
+		}
+		i++
+	}
+	return i, 0, Err{}
+}
+
+// scanLimited scans the string at the beginning of s
+// the length of which must not exceed maxLen.
+// Only the first maxLen+1 bytes of s are scanned since they must contain
+// the closing quote, limitCode is returned along with the tail
+// beginning at the first byte beyond the limit otherwise
+func scanLimited(
+	s string,
+	maxLen int,
+	scan func(s string, maxLen int) (string, string, ErrorCode),
+	limitCode ErrorCode,
+) (string, string, ErrorCode) {
+	sv, tail, errCode := scan(s[:maxLen+1], 0)
+	switch errCode {
+	case 0, ErrControlCharInString:
+		return sv, s[len(sv)+1:], errCode
+	case ErrUnterminatedString:
+		return "", s[maxLen:], limitCode
+	}
+	return sv, tail, errCode
+}
+
+// controlChar returns the index of the first control character in s,
+// or -1 if there is none
+func controlChar(s string) int {
+	for i := 0; i < len(s); i++ {
+		if s[i] < 0x20 {
+			return i
+		}
+	}
+	return -1
+}
+
+// isPlain returns true if s contains
+// neither backslashes nor control characters
+func isPlain(s string) bool {
+	const (
+		lsb = 0x0101010101010101
+		msb = 0x8080808080808080
+	)
+	for ; len(s) >= 8; s = s[8:] {
+		// Check 8 bytes at once, the most significant bit of a byte
+		// is set if it's less than 0x20 or if it's a backslash
+		x := uint64(s[0]) | uint64(s[1])<<8 |
+			uint64(s[2])<<16 | uint64(s[3])<<24 |
+			uint64(s[4])<<32 | uint64(s[5])<<40 |
+			uint64(s[6])<<48 | uint64(s[7])<<56
+		y := x ^ ('\\' * lsb)
+		if ((x-0x20*lsb)&^x|(y-lsb)&^y)&msb != 0 {
+			return false
+		}
+	}
+	for i := 0; i < len(s); i++ {
+		if s[i] < 0x20 || s[i] == '\\' {
+			return false
+		}
+	}
+	return true
+}
+
+// invalidUTF8 returns the index of the first byte of s
+// that isn't part of a valid UTF-8 sequence, or -1 if s is valid
+func invalidUTF8(s string) int {
+	if utf8.ValidString(s) {
+		// Fast path - mostly ASCII
+		return -1
+	}
+	for i := 0; i < len(s); {
+		if s[i] < utf8.RuneSelf {
+			i++
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if r == utf8.RuneError && size == 1 {
+			return i
+		}
+		i += size
+	}
+	return -1
+}
+
+// loneSurrogate returns the index of the first \u escape sequence
+// in the valid raw string s encoding an unpaired surrogate
+// along with the error code telling whether it's a high or a low one
+func loneSurrogate(s string) (int, ErrorCode) {
+	i := 0
+	for {
+		n := strings.IndexByte(s[i:], '\\')
+		if n < 0 {
+			return -1, 0
+		}
+		i += n
+		if s[i+1] != 'u' {
+			i += 2
+			continue
+		}
+		r := parseHex4(s[i+2:])
+		switch {
+		case r >= 0xD800 && r < 0xDC00:
+			// High surrogate, must be followed by a low one
+			if len(s)-i < 12 || s[i+6] != '\\' || s[i+7] != 'u' {
+				return i, ErrLoneHighSurrogate
+			}
+			if l := parseHex4(s[i+8:]); l < 0xDC00 || l > 0xDFFF {
+				return i, ErrLoneHighSurrogate
+			}
+			i += 12
+		case r >= 0xDC00 && r <= 0xDFFF:
+			// Low surrogate not preceded by a high one
+			return i, ErrLoneLowSurrogate
 		default:
-			// Unknown escape sequence
-			return rs, tail, 402
+			i += 6
 		}
 	}
 }
 
-func scanNumber(s string) (string, int) {
-	if len(s) == 0 {
-		// zero-length number
-		return s, 700
+// noncharacter returns the index of the first noncharacter
+// in the valid raw string s, or -1 if there's none.
+// Escaped noncharacters are reported at their escape sequence
+func noncharacter(s string) int {
+	for i := 0; i < len(s); {
+		switch c := s[i]; {
+		case c == '\\':
+			if s[i+1] != 'u' {
+				if s[i+1] < utf8.RuneSelf {
+					i += 2
+				} else {
+					// The escaped character is checked on its own
+					i++
+				}
+				continue
+			}
+			r, size := parseHex4(s[i+2:]), 6
+			if utf16.IsSurrogate(r) && len(s)-i >= 12 &&
+				s[i+6] == '\\' && s[i+7] == 'u' {
+				if p := utf16.DecodeRune(r, parseHex4(s[i+8:])); p != utf8.RuneError {
+					r, size = p, 12
+				}
+			}
+			if isNoncharacter(r) {
+				return i
+			}
+			i += size
+		case c < utf8.RuneSelf:
+			i++
+		default:
+			r, size := utf8.DecodeRuneInString(s[i:])
+			if isNoncharacter(r) {
+				return i
+			}
+			i += size
+		}
 	}
+	return -1
+}
+
+// isNoncharacter returns true for the code points
+// U+FDD0 through U+FDEF and U+nFFFE and U+nFFFF of each plane n
+func isNoncharacter(r rune) bool {
+	return r >= 0xFDD0 && r <= 0xFDEF || r&0xFFFE == 0xFFFE
+}
+
+// scanNumber scans the number at the beginning of s.
+// If maxDigits is exceeded the returned tail
+// begins at the first digit beyond the limit
+func scanNumber(s string, maxDigits int) (string, ErrorCode) {
 	if s[0] == '-' {
 		s = s[1:]
 		if len(s) == 0 {
 			// missing number after minus
-			return s, 701
+			return s, ErrExpectedDigit
 		}
 	}
 	i := 0
@@ -386,11 +1620,15 @@ func scanNumber(s string) (string, int) {
 	}
 	if i <= 0 {
 		// non 0..9 digit
-		return s, 702
+		return s, ErrExpectedDigit
 	}
 	if s[0] == '0' && i != 1 {
 		// unexpected number starting from 0
-		return s, 703
+		return s, ErrLeadingZero
+	}
+	digits := i
+	if maxDigits > 0 && digits > maxDigits {
+		return s[maxDigits:], ErrMaxNumberDigits
 	}
 	if i >= len(s) {
 		return "", 0
@@ -400,7 +1638,7 @@ func scanNumber(s string) (string, int) {
 		s = s[i+1:]
 		if len(s) == 0 {
 			// Missing fractional part
-			return s, 704
+			return s, ErrExpectedFractionDigit
 		}
 		i = 0
 		for i < len(s) {
@@ -411,7 +1649,11 @@ func scanNumber(s string) (string, int) {
 		}
 		if i == 0 {
 			// Expecting 0..9 digit in fractional part
-			return s, 705
+			return s, ErrExpectedFractionDigit
+		}
+		digits += i
+		if maxDigits > 0 && digits > maxDigits {
+			return s[i-(digits-maxDigits):], ErrMaxNumberDigits
 		}
 		if i >= len(s) {
 			return "", 0
@@ -422,13 +1664,13 @@ func scanNumber(s string) (string, int) {
 		s = s[i+1:]
 		if len(s) == 0 {
 			// Missing exponent part
-			return s, 706
+			return s, ErrExpectedExponentDigit
 		}
 		if s[0] == '-' || s[0] == '+' {
 			s = s[1:]
 			if len(s) == 0 {
 				// Missing exponent part
-				return s, 707
+				return s, ErrExpectedExponentDigit
 			}
 		}
 		i = 0
@@ -440,7 +1682,11 @@ func scanNumber(s string) (string, int) {
 		}
 		if i == 0 {
 			// Expecting 0..9 digit in exponent part
-			return s, 708
+			return s, ErrExpectedExponentDigit
+		}
+		digits += i
+		if maxDigits > 0 && digits > maxDigits {
+			return s[i-(digits-maxDigits):], ErrMaxNumberDigits
 		}
 		if i >= len(s) {
 			return "", 0
@@ -449,11 +1695,11 @@ func scanNumber(s string) (string, int) {
 	return s[i:], 0
 }
 
-func scanRawString(s string) (string, string, int) {
+func scanRawString(s string) (string, string, ErrorCode) {
 	n := strings.IndexByte(s, '"')
 	if n < 0 {
 		// Missing closing "
-		return s, "", 600
+		return s, "", ErrUnterminatedString
 	}
 	if n == 0 || s[n-1] != '\\' {
 		// Fast path. No escaped ".
@@ -475,7 +1721,7 @@ func scanRawString(s string) (string, string, int) {
 		n = strings.IndexByte(s, '"')
 		if n < 0 {
 			// Missing closing "
-			return ss, "", 601
+			return ss, "", ErrUnterminatedString
 		}
 		if n == 0 || s[n-1] != '\\' {
 			return ss[:len(ss)-len(s)+n], s[n+1:], 0
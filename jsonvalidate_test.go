@@ -1,4 +1,4 @@
-package main
+package jsonvalidate
 
 import (
 	"testing"
@@ -30,7 +30,7 @@ func validValues() []Input {
 		{"number_negative ie", `-1e+2`},
 		{"string", `"okay"`},
 		{"string_with escape sequences", `"a\r\nb\b\t\"\\\/\f\uAAAA"`},
-		{"string_with spaces", "\"\r\n\t foo\""},
+		{"string_with spaces", "\"  foo bar \""},
 		{"array_empty", `[]`},
 		{"array_numbers", `[1, 1.4,5]`},
 		{"array_strings", `["a","b","c"]`},
@@ -44,6 +44,7 @@ func validDocuments() []Input {
 		{"object_1", `{"foo":42}`},
 		{"object_1", `{"foo":"bar"}`},
 		{"object_2", `{ "a" : "b", "c" : "d"}`},
+		{"object_key with escape sequence", `{"a\n":"b"}`},
 		{"object_complex", `{
 			"1": true,
 			"2": false,
@@ -67,99 +68,174 @@ func TestValidateInvalid(t *testing.T) {
 	for _, tt := range []struct {
 		name   string
 		in     string
+		code   ErrorCode
 		offset int
 	}{
 		{
 			"empty input",
-			``, 0,
+			``, ErrEmptyInput, 0,
 		}, {
 			"list of values",
-			`true,false`, 4,
+			`true,false`, ErrTrailingData, 4,
+		}, {
+			"list of values_whitespace separated",
+			`1 2`, ErrTrailingData, 2,
+		}, {
+			"list of documents",
+			`{} {}`, ErrTrailingData, 3,
+		}, {
+			"unterminated array",
+			`[`, ErrUnexpectedEOF, 1,
+		}, {
+			"unterminated object",
+			`{"foo":[]`, ErrUnexpectedEOF, 9,
 		}, {
 			"missing value",
-			`{"foo"}`, 6,
+			`{"foo"}`, ErrExpectedColon, 6,
 		},
 		{
 			"missing collon",
-			`{"foo""bar"}`, 6,
+			`{"foo""bar"}`, ErrExpectedColon, 6,
 		},
 		{
 			"missing closing quote on key",
-			`{"foo}`, 1,
+			`{"foo}`, ErrUnterminatedString, 1,
 		},
 		{
 			"missing quotes on key",
-			`{foo:"bar"}`, 1,
+			`{foo:"bar"}`, ErrExpectedKey, 1,
 		},
 		{
 			"trailing comma after field",
-			`{"x":"y",}`, 9,
+			`{"x":"y",}`, ErrExpectedKey, 9,
 		},
 		{
 			"trailing comma after element",
-			`["x","y",]`, 9,
+			`["x","y",]`, ErrExpectedValue, 9,
+		},
+		{
+			"missing comma after element",
+			`["x" "y"]`, ErrExpectedCommaOrBracket, 5,
 		},
 		{
 			"invalid number value",
-			`{"x":123.23.2}`, 11,
+			`{"x":123.23.2}`, ErrExpectedCommaOrBrace, 11,
 		},
 		{
 			"invalid number value_missing digits",
-			`{"x":-}`, 5,
+			`{"x":-}`, ErrExpectedDigit, 5,
+		},
+		{
+			"invalid number value_leading zero",
+			`[012]`, ErrLeadingZero, 1,
+		},
+		{
+			"invalid number value_missing fraction",
+			`[1.]`, ErrExpectedFractionDigit, 1,
+		},
+		{
+			"invalid number value_missing exponent",
+			`[1e+]`, ErrExpectedExponentDigit, 1,
+		},
+		{
+			"invalid literal",
+			`[nul]`, ErrInvalidNull, 1,
+		},
+		{
+			"invalid literal",
+			`[ture]`, ErrInvalidTrue, 1,
+		},
+		{
+			"invalid literal",
+			`[flase]`, ErrInvalidFalse, 1,
+		},
+
+		// Invalid string
+		{
+			"string with control characters",
+			"\"\r\n\t foo\"", ErrControlCharInString, 1,
 		},
 
 		// Invalid key
 		{
 			"empty key",
-			`{"":""}`, 1,
+			`{"":""}`, ErrEmptyKey, 1,
 		},
 		{
 			"key with invalid escape sequence",
-			`{"x\x":""}`, 1,
+			`{"x\x":""}`, ErrInvalidEscape, 1,
 		},
 		{
 			"key with invalid escape sequence",
-			"{\"x\n\":\"\"}", 1,
+			"{\"x\n\":\"\"}", ErrControlCharInKey, 1,
 		},
 
 		// Invalid escape
 		{
 			"illegal escape sequence",
-			`{"x":"\x"}`, 5,
+			`{"x":"\x"}`, ErrInvalidEscape, 5,
 		},
 		{
 			"illegal escape sequence_too short",
-			`{"x":"\1"}`, 5,
+			`{"x":"\1"}`, ErrInvalidEscape, 5,
 		},
 		{
 			"illegal escape sequence_too short",
-			`{"x":"\12"}`, 5,
+			`{"x":"\12"}`, ErrInvalidEscape, 5,
 		},
 		{
 			"illegal escape sequence_too short",
-			`{"x":"\123"}`, 5,
+			`{"x":"\123"}`, ErrInvalidEscape, 5,
+		},
+		{
+			"illegal escape sequence_too short",
+			`{"x":"\u12"}`, ErrShortUnicodeEscape, 5,
+		},
+		{
+			"illegal escape sequence_invalid hex digit",
+			`{"x":"\u12x3"}`, ErrInvalidUnicodeEscape, 5,
 		},
 
 		// Invalid object
 		{
 			"duplicate key",
-			`{"y": 1, "x": 2, "z": 3, "x": 4}`, 25,
+			`{"y": 1, "x": 2, "z": 3, "x": 4}`, ErrDuplicateKey, 25,
 		},
 		{
 			"duplicate key",
-			`{"x": 1, "x": 2}`, 9,
+			`{"x": 1, "x": 2}`, ErrDuplicateKey, 9,
+		},
+		{
+			"duplicate key_escaped",
+			`{"\u0061": 1, "a": 2}`, ErrDuplicateKey, 14,
+		},
+		{
+			"duplicate key_escaped later",
+			`{"/": 1, "\/": 2}`, ErrDuplicateKey, 9,
+		},
+		{
+			"duplicate key_different escapes",
+			`{"\u00e4\n": 1, "ä\u000A": 2}`, ErrDuplicateKey, 16,
+		},
+		{
+			"duplicate key_surrogate pair",
+			`{"\ud83d\ude00": 1, "😀": 2}`, ErrDuplicateKey, 20,
+		},
+		{
+			"duplicate key_lone surrogate",
+			`{"\ud800": 1, "a": 2, "\ud800": 3}`, ErrDuplicateKey, 22,
 		},
 	} {
 		t.Run(tt.name, func(t *testing.T) {
 			parser := NewParser(0)
 			err := parser.Validate(tt.in, opts)
-			require.NotZero(t, err.DebugCode, "unexpected debug code")
+			require.Equal(t, tt.code, err.DebugCode, "unexpected debug code")
 			require.Equal(t, tt.offset, err.Offset, "unexpected error offset")
 		})
 		t.Run(tt.name+"_bytes", func(t *testing.T) {
 			parser := NewParser(0)
 			err := parser.ValidateBytes([]byte(tt.in), opts)
-			require.NotZero(t, err.DebugCode, "unexpected debug code")
+			require.Equal(t, tt.code, err.DebugCode, "unexpected debug code")
 			require.Equal(t, tt.offset, err.Offset, "unexpected error offset")
 		})
 	}
@@ -187,6 +263,20 @@ func TestValidateValid(t *testing.T) {
 	}
 }
 
+func TestValidateDistinctEscapedKeys(t *testing.T) {
+	for _, in := range []string{
+		`{"\\": 1, "\\\\": 2}`,
+		`{"\\u0061": 1, "a": 2}`,
+		`{"a\u0000": 1, "a": 2}`,
+		`{"\ud83d\ude00": 1, "\ud83d\ude01": 2}`,
+		`{"\ud800":1,"\udbff":2}`,
+		`{"\ud800": 1, "\ufffd": 2}`,
+	} {
+		err := NewParser(0).Validate(in, Options{})
+		require.Zero(t, err.DebugCode, in)
+	}
+}
+
 func TestValidateIgnoreDuplicateKeys(t *testing.T) {
 	parser := NewParser(0)
 	err := parser.Validate(`{"x":1,"x":2}`, Options{
@@ -198,6 +288,17 @@ func TestValidateIgnoreDuplicateKeys(t *testing.T) {
 	)
 }
 
+func TestValidateAllowControlChars(t *testing.T) {
+	parser := NewParser(0)
+	err := parser.Validate("\"\r\n\t foo\"", Options{
+		AllowControlChars: true,
+	})
+	require.Zero(
+		t, err.DebugCode,
+		"unexpected debug code at offset: %d", err.Offset,
+	)
+}
+
 func TestValidateDocumentInvalid(t *testing.T) {
 	opts := Options{
 		ExpectDocument: true,
@@ -242,3 +343,47 @@ func TestValidateDocument(t *testing.T) {
 		})
 	}
 }
+
+func TestScanStrict(t *testing.T) {
+	inputs := append(validValues(), validDocuments()...)
+	inputs = append(inputs, []Input{
+		{"medium", mdValid},
+		{"large", lgValid},
+		{"empty", ``},
+		{"trailing data", `[1] 2`},
+		{"trailing comma", `[1,]`},
+		{"missing colon", `{"a" 1}`},
+		{"missing value", `{"a":}`},
+		{"unterminated array", `[1, [2`},
+		{"unterminated object", `{"a":1`},
+		{"mismatched brackets", `[1}`},
+		{"mismatched braces", `{"a":1]`},
+		{"empty key", `{"":1}`},
+		{"duplicate key", `{"a":1,"a":2}`},
+		{"control char", "[\"\t\"]"},
+		{"invalid literal", `[nul]`},
+		{"invalid number", `[01]`},
+		{"deep", `[[[[{"a":[{}]}]]]]`},
+		{"long string", `["abcdefghij"]`},
+	}...)
+	for _, opts := range []Options{
+		{},
+		{AllowDuplicateKeys: true},
+		{Profile: ECMA404},
+		{MaxDepth: 4},
+		{MaxStringBytes: 8, MaxKeyBytes: 8, MaxNumberDigits: 4},
+	} {
+		for _, tt := range inputs {
+			pool := NewParser(0).stackPool
+			var strict, general scanner
+			strict.init(pool, opts, false)
+			general.init(pool, opts, false)
+			require.True(t, strict.strict(tt.Source), tt.Name)
+			_, err := general.scan(tt.Source, true)
+			require.Equal(t,
+				err.DebugCode == 0, strict.scanStrict(tt.Source),
+				"%s: %#v", tt.Name, opts,
+			)
+		}
+	}
+}
@@ -0,0 +1,225 @@
+package jsonvalidate
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMaxDepth(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		in       string
+		maxDepth int
+		offset   int
+		path     string
+	}{
+		{"array", `[[1]]`, 1, 1, `/0`},
+		{"object", `{"a":{"b":{}}}`, 2, 10, `/a/b`},
+		{"mixed", `[{}, [], {"x": [[]]}]`, 3, 16, `/2/x/0`},
+		{"hostile", strings.Repeat(`[`, 1000000), 64, 64, strings.Repeat(`/0`, 64)},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := Options{MaxDepth: tt.maxDepth}
+			err := NewParser(0).Validate(tt.in, opts)
+			require.Equal(t, ErrMaxDepth, err.DebugCode)
+			require.Equal(t, tt.offset, err.Offset)
+			require.Equal(t, tt.path, err.Path)
+
+			v := NewParser(0).NewValidator(opts)
+			require.Equal(t, err, writeParts(v, tt.in, 7))
+		})
+	}
+
+	for _, in := range []string{
+		`[[1]]`,
+		`{"a":{"b":{}}}`,
+		`[{}, [], {"x": []}]`,
+	} {
+		opts := Options{MaxDepth: 3}
+		require.Zero(t, NewParser(0).Validate(in, opts).DebugCode, in)
+		require.Zero(t, NewParser(0).Validate(in, Options{}).DebugCode, in)
+	}
+}
+
+func TestMaxDepthStackLen(t *testing.T) {
+	// The stack mustn't grow beyond the limit
+	const maxDepth = 16
+	var sc scanner
+	sc.init(NewParser(0).stackPool, Options{MaxDepth: maxDepth}, false)
+	_, err := sc.scan(strings.Repeat(`[`, 1024), true)
+	require.Equal(t, ErrMaxDepth, err.DebugCode)
+	_, _, containerLevel := sc.stk.Top()
+	require.Equal(t, maxDepth, containerLevel)
+}
+
+func TestSizeLimits(t *testing.T) {
+	for _, tt := range []struct {
+		name   string
+		in     string
+		opts   Options
+		code   ErrorCode
+		offset int
+		path   string
+	}{
+		{
+			"input",
+			`{"foo": "bar"}`, Options{MaxInputBytes: 10},
+			ErrMaxInputBytes, 10, ``,
+		}, {
+			"string",
+			`["abc", "abcdef"]`, Options{MaxStringBytes: 4},
+			ErrMaxStringBytes, 13, `/1`,
+		}, {
+			"string_escaped quote",
+			`["ab\"", "abc\""]`, Options{MaxStringBytes: 4},
+			ErrMaxStringBytes, 14, `/1`,
+		}, {
+			"string_unterminated",
+			`["abcdef`, Options{MaxStringBytes: 4},
+			ErrMaxStringBytes, 6, `/0`,
+		}, {
+			"key",
+			`{"abc": 1, "abcdef": 2}`, Options{MaxKeyBytes: 4},
+			ErrMaxKeyBytes, 16, ``,
+		}, {
+			"key_escaped",
+			`{"a\nb": {"a\n\n": 2}}`, Options{MaxKeyBytes: 4},
+			ErrMaxKeyBytes, 15, `/a` + "\n" + `b`,
+		}, {
+			"key_not a string value",
+			`{"abc": "abcdef"}`, Options{MaxKeyBytes: 4, MaxStringBytes: 6},
+			0, 0, ``,
+		}, {
+			"number_integer",
+			`[123, -12345]`, Options{MaxNumberDigits: 3},
+			ErrMaxNumberDigits, 10, `/1`,
+		}, {
+			"number_fraction",
+			`[1.23, 12.345]`, Options{MaxNumberDigits: 3},
+			ErrMaxNumberDigits, 11, `/1`,
+		}, {
+			"number_exponent",
+			`[1e23, 1.2e+34]`, Options{MaxNumberDigits: 3},
+			ErrMaxNumberDigits, 13, `/1`,
+		}, {
+			"number_at end",
+			strings.Repeat(`9`, 100), Options{MaxNumberDigits: 50},
+			ErrMaxNumberDigits, 50, ``,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			err := NewParser(0).Validate(tt.in, tt.opts)
+			require.Equal(t, tt.code, err.DebugCode)
+			require.Equal(t, tt.offset, err.Offset)
+			require.Equal(t, tt.path, err.Path)
+			if tt.code == 0 {
+				return
+			}
+
+			// Limits are enforced before the input is complete
+			// so incomplete tokens aren't buffered beyond the limit
+			v := NewParser(0).NewValidator(tt.opts)
+			_, werr := v.Write([]byte(tt.in))
+			require.Equal(t, err, werr)
+
+			v = NewParser(0).NewValidator(tt.opts)
+			require.Equal(t, err, writeParts(v, tt.in, 1))
+		})
+	}
+}
+
+func TestSizeLimitsWithinLimit(t *testing.T) {
+	opts := Options{
+		MaxInputBytes:   33,
+		MaxStringBytes:  4,
+		MaxKeyBytes:     3,
+		MaxNumberDigits: 5,
+	}
+	in := `{"abc": ["ab\"", 12.3e-45, "ä"]}`
+	require.Len(t, in, opts.MaxInputBytes)
+	require.Zero(t, NewParser(0).Validate(in, opts).DebugCode)
+
+	v := NewParser(0).NewValidator(opts)
+	require.NoError(t, writeParts(v, in, 1))
+}
+
+func TestMaxStringBytesStreaming(t *testing.T) {
+	// A string exceeding the limit fails
+	// the write pushing it beyond the limit
+	opts := Options{MaxStringBytes: 8}
+	v := NewParser(0).NewValidator(opts)
+	n, err := v.Write([]byte(`["abcd`))
+	require.NoError(t, err)
+	require.Equal(t, 6, n)
+
+	n, err = v.Write([]byte(`efghijkl`))
+	require.Equal(t, Err{
+		DebugCode: ErrMaxStringBytes,
+		Offset:    10,
+		Line:      1,
+		Column:    11,
+		Path:      "/0",
+	}, err)
+	require.Equal(t, 4, n)
+}
+
+func TestCountLimits(t *testing.T) {
+	for _, tt := range []struct {
+		name   string
+		in     string
+		opts   Options
+		code   ErrorCode
+		offset int
+		path   string
+	}{
+		{
+			"object keys",
+			`{"a": {"x": 1, "y": 2, "z": 3}}`, Options{MaxObjectKeys: 2},
+			ErrMaxObjectKeys, 23, `/a`,
+		}, {
+			"object keys_within limit",
+			`[{"x": 1, "y": 2}, {"z": {}}]`, Options{MaxObjectKeys: 2},
+			0, 0, ``,
+		}, {
+			"array elements",
+			`{"a": [[1, 2], [1, 2, 3]]}`, Options{MaxArrayElements: 2},
+			ErrMaxArrayElements, 20, `/a/1/2`,
+		}, {
+			"array elements_within limit",
+			`[[1, 2], [], [[]]]`, Options{MaxArrayElements: 3},
+			0, 0, ``,
+		}, {
+			"total values",
+			`{"a": [1, {"b": null}], "c": true}`, Options{MaxTotalValues: 5},
+			ErrMaxTotalValues, 29, `/c`,
+		}, {
+			"total values_top level",
+			`"abc"`, Options{MaxTotalValues: 1},
+			0, 0, ``,
+		}, {
+			"total values_within limit",
+			`{"a": [1, {"b": null}], "c": true}`, Options{MaxTotalValues: 6},
+			0, 0, ``,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			err := NewParser(0).Validate(tt.in, tt.opts)
+			require.Equal(t, tt.code, err.DebugCode)
+			require.Equal(t, tt.offset, err.Offset)
+			require.Equal(t, tt.path, err.Path)
+
+			// Values split across parts are counted only once
+			for _, partSize := range []int{1, 2, 3} {
+				v := NewParser(0).NewValidator(tt.opts)
+				verr := writeParts(v, tt.in, partSize)
+				if tt.code == 0 {
+					require.NoError(t, verr)
+					continue
+				}
+				require.Equal(t, err, verr)
+			}
+		})
+	}
+}
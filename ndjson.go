@@ -0,0 +1,93 @@
+package jsonvalidate
+
+import (
+	"bytes"
+	"io"
+)
+
+// ValidateNDJSON validates newline-delimited JSON read from r where each
+// line holds exactly one JSON value. The options apply to each line
+// separately, lines may be terminated by either LF or CRLF.
+// fn is called for each line in order with its number starting at 1
+// and either nil or the Err of the line. Offsets and positions of
+// the errors are relative to the beginning of the input.
+// Validation stops as soon as fn returns false.
+// Blank lines are reported as empty input unless
+// Options.SkipBlankLines is set.
+// Returns nil once the input is validated or stopped,
+// or the error returned by r
+func (pr *Parser) ValidateNDJSON(
+	r io.Reader,
+	opts Options,
+	fn func(line int, err error) bool,
+) error {
+	return pr.validateNDJSON(r, opts, fn, readChunkSize)
+}
+
+func (pr *Parser) validateNDJSON(
+	r io.Reader,
+	opts Options,
+	fn func(line int, err error) bool,
+	chunkSize int,
+) error {
+	v := pr.NewValidator(opts)
+	buf := make([]byte, chunkSize)
+	line, offset := 1, 0
+	// length is the length of the current line, the validator
+	// doesn't count the bytes written after an error
+	length := 0
+	blank := true
+
+	// endLine reports the current line and
+	// prepares the validator for the next one
+	endLine := func() bool {
+		err := v.Close()
+		if blank && opts.SkipBlankLines {
+			err = nil
+		} else if !fn(line, err) {
+			return false
+		}
+		offset += length + 1
+		line++
+		length = 0
+		blank = true
+		v.reset(offset, position{line: line, column: 1})
+		return true
+	}
+
+	for {
+		n, err := r.Read(buf)
+		p := buf[:n]
+		for len(p) > 0 {
+			l := p
+			i := bytes.IndexByte(p, '\n')
+			if i >= 0 {
+				l = p[:i]
+			}
+			blank = blank && len(skipWS(b2s(l))) == 0
+			length += len(l)
+			// Errors are kept by the validator until the end of the line
+			_, _ = v.Write(l)
+			if i < 0 {
+				break
+			}
+			if !endLine() {
+				return nil
+			}
+			p = p[i+1:]
+		}
+		switch err {
+		case nil:
+		case io.EOF:
+			if length > 0 {
+				// The last line isn't terminated
+				endLine()
+			}
+			_ = v.Close()
+			return nil
+		default:
+			_ = v.Close()
+			return err
+		}
+	}
+}
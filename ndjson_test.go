@@ -0,0 +1,134 @@
+package jsonvalidate
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type lineResult struct {
+	Line int
+	Err  error
+}
+
+func validateNDJSON(
+	t *testing.T,
+	in string,
+	opts Options,
+	chunkSize int,
+	stopAt int,
+) []lineResult {
+	t.Helper()
+	var results []lineResult
+	err := NewParser(0).validateNDJSON(
+		strings.NewReader(in), opts,
+		func(line int, err error) bool {
+			results = append(results, lineResult{line, err})
+			return line != stopAt
+		},
+		chunkSize,
+	)
+	require.NoError(t, err)
+	return results
+}
+
+func TestValidateNDJSON(t *testing.T) {
+	for _, tt := range []struct {
+		name   string
+		in     string
+		opts   Options
+		expect []lineResult
+	}{
+		{"empty", ``, Options{}, nil},
+		{"single", `{"a":1}`, Options{}, []lineResult{{1, nil}}},
+		{"terminated", "{\"a\":1}\n", Options{}, []lineResult{{1, nil}}},
+		{"lines", "1\n\"foo\"\n[true, null]\n", Options{}, []lineResult{
+			{1, nil}, {2, nil}, {3, nil},
+		}},
+		{"crlf", "1\r\n{\"a\": [2]}\r\n", Options{}, []lineResult{
+			{1, nil}, {2, nil},
+		}},
+		{"invalid_line", "1\n[1,\n{\"a\":1}\n", Options{}, []lineResult{
+			{1, nil},
+			{2, Err{DebugCode: ErrUnexpectedEOF, Offset: 5, Line: 2, Column: 4, Path: "/1"}},
+			{3, nil},
+		}},
+		{"two_values", "1\n1 2\n", Options{}, []lineResult{
+			{1, nil},
+			{2, Err{DebugCode: ErrTrailingData, Offset: 4, Line: 2, Column: 3}},
+		}},
+		{"value_across_lines", "[1,\n2]", Options{}, []lineResult{
+			{1, Err{DebugCode: ErrUnexpectedEOF, Offset: 3, Line: 1, Column: 4, Path: "/1"}},
+			{2, Err{DebugCode: ErrTrailingData, Offset: 5, Line: 2, Column: 2}},
+		}},
+		{"blank_lines", "1\n\n \t\r\n2", Options{}, []lineResult{
+			{1, nil},
+			{2, Err{DebugCode: ErrEmptyInput, Offset: 2, Line: 2, Column: 1}},
+			{3, Err{DebugCode: ErrEmptyInput, Offset: 6, Line: 3, Column: 4}},
+			{4, nil},
+		}},
+		{"skip_blank_lines", "1\n\n \t\r\n2\n", Options{SkipBlankLines: true}, []lineResult{
+			{1, nil}, {4, nil},
+		}},
+		{"expect_document", "{}\n[]\n{\"a\":1}", Options{ExpectDocument: true}, []lineResult{
+			{1, nil},
+			{2, Err{DebugCode: ErrExpectedObject, Offset: 3, Line: 2, Column: 1}},
+			{3, nil},
+		}},
+		{"duplicate_keys_per_line", "{\"a\":1}\n{\"a\":1,\"a\":2}", Options{}, []lineResult{
+			{1, nil},
+			{2, Err{DebugCode: ErrDuplicateKey, Offset: 15, Line: 2, Column: 8, Path: "/a"}},
+		}},
+		{"max_input_bytes_per_line", "[1,2]\n[1,2,3]\n[3]", Options{MaxInputBytes: 5}, []lineResult{
+			{1, nil},
+			{2, Err{DebugCode: ErrMaxInputBytes, Offset: 11, Line: 2, Column: 6}},
+			{3, nil},
+		}},
+		{"error_before_chunk_boundary", "[1,x, 2, 3, 4, 5, 6, 7, 8]\n[1,y]\n", Options{}, []lineResult{
+			{1, Err{DebugCode: ErrExpectedValue, Offset: 3, Line: 1, Column: 4, Path: "/1"}},
+			{2, Err{DebugCode: ErrExpectedValue, Offset: 30, Line: 2, Column: 4, Path: "/1"}},
+		}},
+		{"max_input_bytes_before_line", "[1,2,3,4]\n[x]", Options{MaxInputBytes: 5}, []lineResult{
+			{1, Err{DebugCode: ErrMaxInputBytes, Offset: 5, Line: 1, Column: 6}},
+			{2, Err{DebugCode: ErrExpectedValue, Offset: 11, Line: 2, Column: 2, Path: "/0"}},
+		}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, chunkSize := range readerChunkSizes {
+				results := validateNDJSON(t, tt.in, tt.opts, chunkSize, 0)
+				require.Equal(t, tt.expect, results, "chunk size: %d", chunkSize)
+			}
+		})
+	}
+}
+
+func TestValidateNDJSONStop(t *testing.T) {
+	in := "1\n[\n3\n4\n"
+	for _, chunkSize := range readerChunkSizes {
+		results := validateNDJSON(t, in, Options{}, chunkSize, 2)
+		require.Len(t, results, 2, "chunk size: %d", chunkSize)
+		require.Equal(t, 2, results[1].Line)
+		require.Error(t, results[1].Err)
+	}
+}
+
+func TestValidateNDJSONReadError(t *testing.T) {
+	readErr := errors.New("read failed")
+	var lines []int
+	err := NewParser(0).ValidateNDJSON(
+		io.MultiReader(
+			strings.NewReader("1\n2\n3"), &failingReader{err: readErr},
+		),
+		Options{},
+		func(line int, err error) bool {
+			require.NoError(t, err)
+			lines = append(lines, line)
+			return true
+		},
+	)
+	require.Equal(t, readErr, err)
+	require.Equal(t, []int{1, 2}, lines)
+}
@@ -0,0 +1,81 @@
+package jsonvalidate
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAllowNonFiniteNumbers(t *testing.T) {
+	opts := Options{AllowNonFiniteNumbers: true}
+	for _, tt := range []struct {
+		name string
+		in   string
+	}{
+		{"nan", `NaN`},
+		{"infinity", `Infinity`},
+		{"negative_infinity", `-Infinity`},
+		{"whitespace", " -Infinity\n"},
+		{"array", `[NaN,Infinity,-Infinity,1,-1]`},
+		{"object", `{"a":NaN,"b":Infinity,"c":-Infinity}`},
+		{"python", `{"mean": NaN, "max": Infinity, "min": -Infinity}`},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Zero(t, validateParts(t, opts, tt.in))
+			require.NotZero(t, NewParser(0).Validate(tt.in, Options{}).DebugCode)
+		})
+	}
+}
+
+func TestAllowNonFiniteNumbersInvalid(t *testing.T) {
+	opts := Options{AllowNonFiniteNumbers: true}
+	for _, tt := range []struct {
+		name   string
+		in     string
+		expect Err
+	}{
+		{"positive_infinity", `+Infinity`,
+			Err{DebugCode: ErrExpectedValue, Offset: 0, Line: 1, Column: 1}},
+		{"negative_nan", `-NaN`,
+			Err{DebugCode: ErrExpectedDigit, Offset: 0, Line: 1, Column: 1}},
+		{"lowercase_nan", `nan`,
+			Err{DebugCode: ErrInvalidNull, Offset: 0, Line: 1, Column: 1}},
+		{"lowercase_infinity", `[infinity]`,
+			Err{DebugCode: ErrExpectedValue, Offset: 1, Line: 1, Column: 2, Path: "/0"}},
+		{"misspelled_infinity", `[Infinty]`,
+			Err{DebugCode: ErrInvalidInfinity, Offset: 1, Line: 1, Column: 2, Path: "/0"}},
+		{"misspelled_negative_infinity", `{"a":-Inf}`,
+			Err{DebugCode: ErrInvalidInfinity, Offset: 5, Line: 1, Column: 6, Path: "/a"}},
+		{"misspelled_nan", `[NAN]`,
+			Err{DebugCode: ErrInvalidNaN, Offset: 1, Line: 1, Column: 2, Path: "/0"}},
+		{"truncated_nan", `Na`,
+			Err{DebugCode: ErrInvalidNaN, Offset: 0, Line: 1, Column: 1}},
+		{"truncated_infinity", `-Infin`,
+			Err{DebugCode: ErrInvalidInfinity, Offset: 0, Line: 1, Column: 1}},
+		{"trailing_letters", `Infinityy`,
+			Err{DebugCode: ErrTrailingData, Offset: 8, Line: 1, Column: 9}},
+		{"key", `{NaN:1}`,
+			Err{DebugCode: ErrExpectedKey, Offset: 1, Line: 1, Column: 2}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.expect, validateParts(t, opts, tt.in))
+		})
+	}
+}
+
+func TestAllowNonFiniteNumbersMaxTotalValues(t *testing.T) {
+	opts := Options{AllowNonFiniteNumbers: true, MaxTotalValues: 3}
+	require.Zero(t, NewParser(0).Validate(`[NaN,-Infinity]`, opts))
+	require.Equal(t,
+		Err{DebugCode: ErrMaxTotalValues, Offset: 15, Line: 1, Column: 16, Path: "/2"},
+		NewParser(0).Validate(`[NaN,-Infinity,Infinity]`, opts),
+	)
+}
+
+func TestAllowNonFiniteNumbersProfiles(t *testing.T) {
+	for _, p := range []Profile{RFC8259, ECMA404, IJSON} {
+		testProfile(t, Options{Profile: p, AllowNonFiniteNumbers: true}, []profileCase{
+			{p.String(), `NaN`, ErrExpectedValue, 0},
+		})
+	}
+}
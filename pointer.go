@@ -0,0 +1,178 @@
+package jsonvalidate
+
+import (
+	"strconv"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	"github.com/romshark/jsonvalidate-go/internal/stack"
+)
+
+// pointer returns the RFC 6901 JSON Pointer of the value
+// the scanner is currently in. It's only computed for errors
+// since it's built from the keys and element counts on the stack
+func (sc *scanner) pointer() string {
+	_, _, containerLevel := sc.stk.Top()
+	if containerLevel < 1 {
+		return ""
+	}
+
+	var b strings.Builder
+	for level := 0; level < containerLevel; level++ {
+		containerType, numElements, key := sc.stk.At(level)
+		if numElements < 1 {
+			// No element scanned yet, the container itself is the value
+			continue
+		}
+		switch containerType {
+		case stack.Object:
+			if level == containerLevel-1 &&
+				(sc.state == stateKey || sc.state == stateFirstKey) {
+				// The key of the previous field is no longer relevant
+				continue
+			}
+			if sc.rules.allowDuplicateKeys {
+				// Keys are only decoded when checked for duplicates
+				key = unescape(key)
+			} else {
+				key = replaceSurrogates(key)
+			}
+			b.WriteByte('/')
+			writeReferenceToken(&b, key)
+		case stack.Array:
+			b.WriteByte('/')
+			b.WriteString(strconv.Itoa(numElements - 1))
+		}
+	}
+	return b.String()
+}
+
+// writeReferenceToken writes the key escaping '~' and '/'
+func writeReferenceToken(b *strings.Builder, key string) {
+	for i := 0; i < len(key); i++ {
+		switch key[i] {
+		case '~':
+			b.WriteString("~0")
+		case '/':
+			b.WriteString("~1")
+		default:
+			b.WriteByte(key[i])
+		}
+	}
+}
+
+// unescape returns the value of the given valid raw string
+// including JSON5 strings and identifiers.
+// Unpaired surrogates are replaced by U+FFFD
+func unescape(s string) string {
+	return decode(s, false)
+}
+
+// unescapeKey is similar to unescape but keeps unpaired surrogates
+// in their generalized UTF-8 encoding so that keys differing only
+// in them are told apart
+func unescapeKey(s string) string {
+	return decode(s, true)
+}
+
+func decode(s string, keepSurrogates bool) string {
+	n := strings.IndexByte(s, '\\')
+	if n < 0 {
+		// Fast path - no escape sequences
+		return s
+	}
+
+	b := make([]byte, 0, len(s))
+	for n >= 0 {
+		b = append(b, s[:n]...)
+		ch := s[n+1]
+		s = s[n+2:]
+		switch ch {
+		case 'b':
+			b = append(b, '\b')
+		case 'f':
+			b = append(b, '\f')
+		case 'n':
+			b = append(b, '\n')
+		case 'r':
+			b = append(b, '\r')
+		case 't':
+			b = append(b, '\t')
+		case 'v':
+			b = append(b, '\v')
+		case '0':
+			b = append(b, 0)
+		case 'x':
+			v, _ := strconv.ParseUint(s[:2], 16, 8)
+			s = s[2:]
+			b = appendRune(b, rune(v))
+		case '\r':
+			// Line continuation
+			if len(s) > 0 && s[0] == '\n' {
+				s = s[1:]
+			}
+		case '\n':
+			// Line continuation
+		case 'u':
+			r := parseHex4(s)
+			s = s[4:]
+			if utf16.IsSurrogate(r) {
+				p := utf8.RuneError
+				if len(s) >= 6 && s[0] == '\\' && s[1] == 'u' {
+					if p = utf16.DecodeRune(r, parseHex4(s[2:])); p != utf8.RuneError {
+						s = s[6:]
+					}
+				}
+				if p == utf8.RuneError && keepSurrogates {
+					b = appendSurrogate(b, r)
+					break
+				}
+				r = p
+			}
+			b = appendRune(b, r)
+		default:
+			if ch == 0xE2 && (strings.HasPrefix(s, "\x80\xA8") ||
+				strings.HasPrefix(s, "\x80\xA9")) {
+				// Line continuation by U+2028 or U+2029
+				s = s[2:]
+				break
+			}
+			// '"', '\\', '/' and in JSON5 any other character
+			// stand for themselves
+			b = append(b, ch)
+		}
+		n = strings.IndexByte(s, '\\')
+	}
+	b = append(b, s...)
+	return b2s(b)
+}
+
+// parseHex4 parses the 4 hexadecimal digits at the beginning of s
+func parseHex4(s string) rune {
+	v, _ := strconv.ParseUint(s[:4], 16, 16)
+	return rune(v)
+}
+
+func appendRune(b []byte, r rune) []byte {
+	var buf [utf8.UTFMax]byte
+	n := utf8.EncodeRune(buf[:], r)
+	return append(b, buf[:n]...)
+}
+
+// appendSurrogate appends the generalized UTF-8 encoding
+// of the surrogate r which utf8.EncodeRune refuses to encode
+func appendSurrogate(b []byte, r rune) []byte {
+	return append(b, 0xE0|byte(r>>12), 0x80|byte(r>>6)&0x3F, 0x80|byte(r)&0x3F)
+}
+
+// replaceSurrogates replaces the generalized UTF-8 encoded
+// surrogates kept by unescapeKey with U+FFFD
+func replaceSurrogates(key string) string {
+	for i := 0; i+2 < len(key); i++ {
+		if key[i] == 0xED && key[i+1] >= 0xA0 {
+			key = key[:i] + string(utf8.RuneError) + key[i+3:]
+		}
+	}
+	return key
+}
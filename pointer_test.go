@@ -0,0 +1,56 @@
+package jsonvalidate
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestErrPath(t *testing.T) {
+	for _, tt := range []struct {
+		input string
+		path  string
+	}{
+		{`x`, ``},
+		{`[x`, `/0`},
+		{`[1,x`, `/1`},
+		{`{"items":[{"price":1},{"price":x}]}`, `/items/1/price`},
+		{`{"a":1,"b":2 x`, `/b`},
+		{`{"a":1,x`, ``},
+		{`{"a":[1,2] x`, `/a`},
+		{`{"a":{}, "b": [[], [0, 1, "x`, `/b/1/2`},
+		{`{"a/b":{"c~d":x}}`, `/a~1b/c~0d`},
+		{`{"A\n😀\/":x}`, "/A\n\U0001F600~1"},
+		{`{"\ud83d":x}`, "/�"},
+		{`{"a\udc00b\udbff":x}`, "/a�b�"},
+		{`{"a":{"x":1,"x":2}}`, `/a/x`},
+		{`{"a":{"\u0078":1,"x":2}}`, `/a/x`},
+		{`{"\\u0041":x}`, `/\u0041`},
+		{`{"a":{"x":1,"y"`, `/a/y`},
+	} {
+		t.Run(tt.input, func(t *testing.T) {
+			err := NewParser(0).Validate(tt.input, Options{})
+			require.NotZero(t, err.DebugCode)
+			require.Equal(t, tt.path, err.Path)
+
+			// The Validator reports the same path
+			// with keys written in separate parts
+			v := NewParser(0).NewValidator(Options{})
+			require.Equal(t, err, writeParts(v, tt.input, 1))
+		})
+	}
+}
+
+func TestErrPathDeep(t *testing.T) {
+	in := strings.Repeat(`{"a":[`, 100) + `x`
+	err := NewParser(0).Validate(in, Options{})
+	require.Equal(t, ErrExpectedValue, err.DebugCode)
+	require.Equal(t, strings.Repeat(`/a/0`, 100), err.Path)
+}
+
+func TestErrPathAllowDuplicateKeys(t *testing.T) {
+	opts := Options{AllowDuplicateKeys: true}
+	err := NewParser(0).Validate(`{"\\u0041":{"\u0042":x}}`, opts)
+	require.Equal(t, `/\u0041/B`, err.Path)
+}
@@ -0,0 +1,47 @@
+package jsonvalidate
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// position represents the line and column following
+// a part of the input. Lines are terminated by LF,
+// a CR preceding LF is part of the line terminator.
+// Columns are counted in runes.
+// Both lines and columns are counted from 1
+type position struct {
+	line, column int
+}
+
+func startPosition() position {
+	return position{line: 1, column: 1}
+}
+
+// advance moves the position to the end of s
+// which must directly follow the current position
+func (p *position) advance(s string) {
+	if i := strings.LastIndexByte(s, '\n'); i >= 0 {
+		p.line += strings.Count(s[:i], "\n") + 1
+		p.column = 1
+		s = s[i+1:]
+	}
+	p.column += utf8.RuneCountInString(s)
+}
+
+// at returns the position of the end of s
+// which must directly follow the current position
+func (p position) at(s string) position {
+	p.advance(s)
+	return p
+}
+
+// setPosition sets the line and column of err
+// given the input it was found in
+func (err *Err) setPosition(input string) {
+	if err.Offset > len(input) {
+		return
+	}
+	p := startPosition().at(input[:err.Offset])
+	err.Line, err.Column = p.line, p.column
+}
@@ -0,0 +1,59 @@
+package jsonvalidate
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestErrPosition(t *testing.T) {
+	for _, tt := range []struct {
+		name   string
+		in     string
+		offset int
+		line   int
+		column int
+	}{
+		{
+			"first line",
+			`{"x": tru}`, 6, 1, 7,
+		}, {
+			"beginning of line",
+			"{\n\"x\": 1,\nx}", 10, 3, 1,
+		}, {
+			"LF",
+			"{\n  \"x\": 1,\n  \"y\": tru\n}", 19, 3, 8,
+		}, {
+			"CRLF",
+			"{\r\n  \"x\": 1,\r\n  \"y\": tru\r\n}", 21, 3, 8,
+		}, {
+			"end of input",
+			"[\r\n  1,\r\n  2\r\n", 14, 4, 1,
+		}, {
+			"multi-byte runes",
+			"{\"äöü\": \"日本語\", \"x\": -}", 29, 1, 21,
+		}, {
+			"multi-byte runes on previous line",
+			"[\"日本語\",\n  -]", 16, 2, 3,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			err := NewParser(0).Validate(tt.in, Options{})
+			require.NotZero(t, err.DebugCode)
+			require.Equal(t, tt.offset, err.Offset, "unexpected offset")
+			require.Equal(t, tt.line, err.Line, "unexpected line")
+			require.Equal(t, tt.column, err.Column, "unexpected column")
+		})
+		for _, partSize := range readerChunkSizes {
+			t.Run(tt.name+"_validator", func(t *testing.T) {
+				v := NewParser(0).NewValidator(Options{})
+				err := writeParts(v, tt.in, partSize)
+				require.IsType(t, Err{}, err)
+				e := err.(Err)
+				require.Equal(t, tt.offset, e.Offset, "unexpected offset")
+				require.Equal(t, tt.line, e.Line, "unexpected line")
+				require.Equal(t, tt.column, e.Column, "unexpected column")
+			})
+		}
+	}
+}
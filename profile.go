@@ -0,0 +1,158 @@
+package jsonvalidate
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Profile selects the set of conformance rules the input is validated against
+type Profile byte
+
+// Profiles
+const (
+	// Legacy is the default profile. Object keys must not be empty
+	// and all other rules are defined by the options
+	Legacy Profile = iota
+
+	// RFC8259 accepts exactly the JSON grammar of RFC 8259.
+	// Empty and duplicate keys are allowed,
+	// the input must be valid UTF-8
+	RFC8259
+
+	// ECMA404 accepts exactly the JSON grammar of ECMA-404.
+	// It's similar to RFC8259 but doesn't require UTF-8
+	ECMA404
+
+	// IJSON accepts I-JSON messages as defined by RFC 7493.
+	// The input must be valid UTF-8, keys must be unique,
+	// \u escape sequences must not encode unpaired surrogates,
+	// strings must not contain noncharacters
+	// and numbers must be within the magnitude and precision
+	// of IEEE 754 double precision numbers
+	IJSON
+)
+
+var profileNames = map[Profile]string{
+	Legacy:  "legacy",
+	RFC8259: "rfc8259",
+	ECMA404: "ecma404",
+	IJSON:   "ijson",
+}
+
+// String returns the name of the profile
+func (p Profile) String() string {
+	if n, ok := profileNames[p]; ok {
+		return n
+	}
+	return "profile (" + strconv.Itoa(int(p)) + ")"
+}
+
+// rules are the conformance rules resolved from the options.
+// The options relaxing the rules are only honored by the Legacy profile
+// while the options tightening them are honored by all profiles
+type rules struct {
+	allowEmptyKeys      bool
+	allowDuplicateKeys  bool
+	allowControlChars   bool
+	allowComments       bool
+	allowTrailingCommas bool
+	allowNonFinite      bool
+	json5               bool
+	validateUTF8        bool
+	validateSurrogates  bool
+	rejectNoncharacters bool
+	validateNumbers     bool
+}
+
+// rules resolves the conformance rules of opts.Profile
+func (opts Options) rules() rules {
+	r := rules{
+		validateUTF8:       opts.ValidateUTF8,
+		validateSurrogates: opts.ValidateSurrogates,
+	}
+	switch opts.Profile {
+	case RFC8259:
+		r.allowEmptyKeys = true
+		r.allowDuplicateKeys = true
+		r.validateUTF8 = true
+	case ECMA404:
+		r.allowEmptyKeys = true
+		r.allowDuplicateKeys = true
+	case IJSON:
+		r.allowEmptyKeys = true
+		r.validateUTF8 = true
+		r.validateSurrogates = true
+		r.rejectNoncharacters = true
+		r.validateNumbers = true
+	default:
+		r.allowDuplicateKeys = opts.AllowDuplicateKeys
+		r.allowControlChars = opts.AllowControlChars
+		r.allowComments = opts.AllowComments
+		r.allowTrailingCommas = opts.AllowTrailingCommas
+		r.allowNonFinite = opts.AllowNonFiniteNumbers
+	}
+	if opts.Dialect == JSON5 {
+		r.json5 = true
+		r.allowComments = true
+		r.allowTrailingCommas = true
+	}
+	return r
+}
+
+// maxSafeInteger is the greatest integer 2^53-1 all integers up to which
+// are exactly representable as IEEE 754 double precision numbers
+const maxSafeInteger = "9007199254740991"
+
+// isDoubleNumber returns true if the valid number s is within
+// the magnitude and precision of IEEE 754 double precision numbers.
+// Integers must be within ±(2^53-1), see RFC 7493 section 2.2.
+// Numbers with a fraction or an exponent must not have more
+// significant digits than the closest double reproduces,
+// except for numbers too small which are rounded to zero
+func isDoubleNumber(s string) bool {
+	digits := s
+	if digits[0] == '-' {
+		digits = digits[1:]
+	}
+	for i := 0; i < len(digits); i++ {
+		if digits[i] < '0' || digits[i] > '9' {
+			// Fraction or exponent
+			f, err := strconv.ParseFloat(s, 64)
+			if err != nil || f == 0 {
+				return err == nil
+			}
+			var buf [32]byte
+			return sameDigits(s, b2s(strconv.AppendFloat(buf[:0], f, 'e', -1, 64)))
+		}
+	}
+	if len(digits) != len(maxSafeInteger) {
+		return len(digits) < len(maxSafeInteger)
+	}
+	return digits <= maxSafeInteger
+}
+
+// sameDigits returns true if the numbers a and b
+// have the same significant digits
+func sameDigits(a, b string) bool {
+	a, b = significand(a), significand(b)
+	for {
+		a, b = strings.TrimPrefix(a, "."), strings.TrimPrefix(b, ".")
+		if len(a) == 0 || len(b) == 0 {
+			return len(a) == len(b)
+		}
+		if a[0] != b[0] {
+			return false
+		}
+		a, b = a[1:], b[1:]
+	}
+}
+
+// significand returns the part of the mantissa of the number s
+// from its first to its last non-zero digit
+func significand(s string) string {
+	if i := strings.IndexAny(s, "eE"); i >= 0 {
+		s = s[:i]
+	}
+	s = strings.TrimLeft(s, "+-0.")
+	return strings.TrimRight(s, "0.")
+}
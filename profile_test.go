@@ -0,0 +1,167 @@
+package jsonvalidate
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type profileCase struct {
+	name   string
+	in     string
+	code   ErrorCode
+	offset int
+}
+
+// testProfile validates the cases against the given options
+// both in memory and incrementally
+func testProfile(t *testing.T, opts Options, cases []profileCase) {
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateParts(t, opts, tt.in)
+			require.Equal(t, tt.code, err.DebugCode, "unexpected debug code")
+			require.Equal(t, tt.offset, err.Offset, "unexpected error offset")
+		})
+	}
+}
+
+// validateParts validates in against the given options in memory
+// and returns the error after making sure the validator reports it
+// for each of the readerChunkSizes too
+func validateParts(t *testing.T, opts Options, in string) Err {
+	err := NewParser(0).Validate(in, opts)
+	for _, partSize := range readerChunkSizes {
+		v := NewParser(0).NewValidator(opts)
+		verr := writeParts(v, in, partSize)
+		if err.DebugCode == 0 {
+			require.NoError(t, verr, "part size: %d", partSize)
+			continue
+		}
+		require.Equal(t, err, verr, "part size: %d", partSize)
+	}
+	return err
+}
+
+// specValid are valid according to the JSON grammar of both
+// RFC 8259 and ECMA-404 yet rejected by the Legacy profile by default
+var specValid = []profileCase{
+	{"empty key", `{"": 1}`, 0, 0},
+	{"empty key_nested", `[{"a": {"": ""}}]`, 0, 0},
+	{"duplicate key", `{"a": 1, "a": 2}`, 0, 0},
+	{"lone surrogate escape", `"\ud800"`, 0, 0},
+	{"large integer", `12345678901234567890`, 0, 0},
+	{"large number", `1e400`, 0, 0},
+}
+
+// specInvalid are invalid according to all profiles
+var specInvalid = []profileCase{
+	{"control character", "\"a\tb\"", ErrControlCharInString, 2},
+	{"trailing comma", `[1,]`, ErrExpectedValue, 3},
+	{"leading zero", `01`, ErrLeadingZero, 0},
+	{"single quotes", `'a'`, ErrExpectedValue, 0},
+	{"trailing data", `1 2`, ErrTrailingData, 2},
+}
+
+func TestProfileLegacy(t *testing.T) {
+	testProfile(t, Options{}, append([]profileCase{
+		{"empty key", `{"": 1}`, ErrEmptyKey, 1},
+		{"duplicate key", `{"a": 1, "a": 2}`, ErrDuplicateKey, 9},
+		{"invalid UTF-8", "\"\xff\"", 0, 0},
+		{"lone surrogate escape", `"\ud800"`, 0, 0},
+		{"large integer", `12345678901234567890`, 0, 0},
+		{"large number", `1e400`, 0, 0},
+	}, specInvalid...))
+
+	// Relaxing options are honored
+	testProfile(t, Options{
+		AllowDuplicateKeys: true,
+		AllowControlChars:  true,
+	}, []profileCase{
+		{"duplicate key", `{"a": 1, "a": 2}`, 0, 0},
+		{"control character", "\"a\tb\"", 0, 0},
+	})
+}
+
+func TestProfileRFC8259(t *testing.T) {
+	opts := Options{Profile: RFC8259}
+	testProfile(t, opts, append(append([]profileCase{
+		{"invalid UTF-8", "\"\xff\"", ErrInvalidUTF8, 1},
+		{"invalid UTF-8_key", "{\"\xc3\": 1}", ErrInvalidUTF8, 2},
+	}, specValid...), specInvalid...))
+
+	// Relaxing options aren't honored
+	opts.AllowControlChars = true
+	testProfile(t, opts, []profileCase{
+		{"control character", "\"a\tb\"", ErrControlCharInString, 2},
+	})
+
+	// Tightening options are honored
+	testProfile(t, Options{
+		Profile:            RFC8259,
+		ValidateSurrogates: true,
+		MaxDepth:           1,
+	}, []profileCase{
+		{"lone surrogate escape", `"\ud800"`, ErrLoneHighSurrogate, 1},
+		{"depth", `[[]]`, ErrMaxDepth, 1},
+	})
+}
+
+func TestProfileECMA404(t *testing.T) {
+	opts := Options{Profile: ECMA404}
+	testProfile(t, opts, append(append([]profileCase{
+		{"invalid UTF-8", "\"\xff\"", 0, 0},
+	}, specValid...), specInvalid...))
+
+	opts.ValidateUTF8 = true
+	testProfile(t, opts, []profileCase{
+		{"invalid UTF-8", "\"\xff\"", ErrInvalidUTF8, 1},
+		{"noncharacter", `"\uFFFF"`, 0, 0},
+	})
+}
+
+func TestProfileIJSON(t *testing.T) {
+	testProfile(t, Options{Profile: IJSON}, append([]profileCase{
+		{"empty key", `{"": 1}`, 0, 0},
+		{"duplicate key", `{"a": 1, "a": 2}`, ErrDuplicateKey, 9},
+		{"duplicate key_escaped", `{"\u0061": 1, "a": 2}`, ErrDuplicateKey, 14},
+		{"invalid UTF-8", "\"\xff\"", ErrInvalidUTF8, 1},
+		{"lone surrogate escape", `"\ud800"`, ErrLoneHighSurrogate, 1},
+		{"lone surrogate escape_low", `{"\udc00": 1}`, ErrLoneLowSurrogate, 2},
+		{"surrogate pair", `"\uD83D\uDE00"`, 0, 0},
+
+		// Noncharacters
+		{"noncharacter escape", `"a\uFFFF"`, ErrNoncharacter, 2},
+		{"noncharacter escape_lower case", `["\ufdd0"]`, ErrNoncharacter, 2},
+		{"noncharacter escape_pair", `"\uD83F\uDFFE"`, ErrNoncharacter, 1},
+		{"noncharacter escape_key", `{"\uFDEF": 1}`, ErrNoncharacter, 2},
+		{"noncharacter", "\"ab\U0010FFFF\"", ErrNoncharacter, 3},
+		{"noncharacter_key", "{\"\uFFFE\": 1}", ErrNoncharacter, 2},
+		{"noncharacter_plane 1", "[\"\U0001FFFF\"]", ErrNoncharacter, 2},
+		{"characters", "[\"\uFDCF\uFDF0\uFFFD\U0010FFFD\", \"\\uFDCF\\uFFFD\\uD83D\\uDFFD\"]", 0, 0},
+
+		// Numbers
+		{"max safe integer", `[9007199254740991, -9007199254740991]`, 0, 0},
+		{"integer too large", `[9007199254740992]`, ErrNumberOutOfRange, 1},
+		{"integer too small", `{"a": -9007199254740992}`, ErrNumberOutOfRange, 6},
+		{"integer too long", `[12345678901234567890]`, ErrNumberOutOfRange, 1},
+		{"integer with exponent", `[1e20, 15e300, 9007199254740992.0]`, 0, 0},
+		{"integer with exponent_too_precise", `[12345678901234567890e0]`, ErrNumberOutOfRange, 1},
+		{"integer with fraction", `[9007199254740993.0]`, ErrNumberOutOfRange, 1},
+		{"fraction", `[0.1, -3.141592653589793, 1.10, 0.30000000000000004, 100e-2]`, 0, 0},
+		{"fraction too precise", `[3.141592653589793238462643383279]`, ErrNumberOutOfRange, 1},
+		{"fraction too precise_exponent", `{"a": 1.00000000000000001E-5}`, ErrNumberOutOfRange, 6},
+		{"fraction too precise_rounded", `[0.12345678901234567]`, ErrNumberOutOfRange, 1},
+		{"max double", `[1.7976931348623157e308, -1.7976931348623157E+308]`, 0, 0},
+		{"beyond max double", `[1.7976931348623159e308]`, ErrNumberOutOfRange, 1},
+		{"beyond max double_negative", `[-1e309]`, ErrNumberOutOfRange, 1},
+		{"tiny number", `[1e-400, 5e-324, 0.0]`, 0, 0},
+	}, specInvalid...))
+}
+
+func TestProfileString(t *testing.T) {
+	require.Equal(t, "legacy", Legacy.String())
+	require.Equal(t, "rfc8259", RFC8259.String())
+	require.Equal(t, "ecma404", ECMA404.String())
+	require.Equal(t, "ijson", IJSON.String())
+	require.Equal(t, "profile (42)", Profile(42).String())
+}
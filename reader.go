@@ -0,0 +1,40 @@
+package jsonvalidate
+
+import "io"
+
+// readChunkSize defines the number of bytes ValidateReader reads at once
+const readChunkSize = 1024 * 64
+
+// ValidateReader validates a JSON value read from r.
+// The input is read in chunks of fixed size and only the token
+// that's currently being scanned is kept in memory (see Validator).
+// Returns nil if the input is valid, an Err if it isn't,
+// or the error returned by r
+func (pr *Parser) ValidateReader(r io.Reader, opts Options) error {
+	return pr.validateReader(r, opts, readChunkSize)
+}
+
+func (pr *Parser) validateReader(
+	r io.Reader,
+	opts Options,
+	chunkSize int,
+) error {
+	v := pr.NewValidator(opts)
+	buf := make([]byte, chunkSize)
+	for {
+		n, err := r.Read(buf)
+		if n > 0 {
+			if _, err := v.Write(buf[:n]); err != nil {
+				return err
+			}
+		}
+		switch err {
+		case nil:
+		case io.EOF:
+			return v.Close()
+		default:
+			_ = v.Close()
+			return err
+		}
+	}
+}
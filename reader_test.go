@@ -0,0 +1,98 @@
+package jsonvalidate
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/stretchr/testify/require"
+)
+
+var readerChunkSizes = []int{1, 2, 3, 5, 8, readChunkSize}
+
+func TestValidateReaderValid(t *testing.T) {
+	inputs := append(validValues(), validDocuments()...)
+	inputs = append(inputs,
+		Input{"string_long", `"` + strings.Repeat("abc\\u00e4", 64) + `"`},
+		Input{"number_long", strings.Repeat("9", 300) + ".5e-10"},
+	)
+	for _, tt := range inputs {
+		for _, chunkSize := range readerChunkSizes {
+			t.Run(tt.Name, func(t *testing.T) {
+				parser := NewParser(0)
+				err := parser.validateReader(
+					strings.NewReader(tt.Source), Options{}, chunkSize,
+				)
+				require.NoError(t, err, "chunk size: %d", chunkSize)
+			})
+		}
+	}
+}
+
+func TestValidateReaderInvalid(t *testing.T) {
+	for _, in := range []string{
+		``,
+		`   `,
+		`[`,
+		`[1,`,
+		`{"foo":`,
+		`{"foo"`,
+		`{"foo":"bar"`,
+		`"unterminated`,
+		`tru`,
+		`nul`,
+		`fals`,
+		`trux`,
+		`-`,
+		`1.`,
+		`1e+`,
+		`01`,
+		`[1] 2`,
+		`{"x": 1, "x": 2}`,
+		`{"x":"\u12x4"}`,
+		`["abc", "def\q"]`,
+		mediumInvalid,
+	} {
+		expected := NewParser(0).Validate(in, Options{})
+		require.NotZero(t, expected.DebugCode)
+
+		for _, chunkSize := range readerChunkSizes {
+			t.Run(in, func(t *testing.T) {
+				parser := NewParser(0)
+				err := parser.validateReader(
+					strings.NewReader(in), Options{}, chunkSize,
+				)
+				require.Equal(t, expected, err, "chunk size: %d", chunkSize)
+			})
+		}
+	}
+}
+
+func TestValidateReaderDuplicateKeysAcrossChunks(t *testing.T) {
+	// Make sure that keys of previous chunks
+	// are still intact after the buffer is reused
+	in := `{"aaaa":1,"bbbb":2,"cccc":3,"aaaa":4}`
+	parser := NewParser(0)
+	err := parser.validateReader(strings.NewReader(in), Options{}, 4)
+	require.Equal(t, Err{DebugCode: ErrDuplicateKey, Offset: 28, Line: 1, Column: 29, Path: "/aaaa"}, err)
+}
+
+func TestValidateReaderReadError(t *testing.T) {
+	readErr := errors.New("read error")
+	parser := NewParser(0)
+	err := parser.ValidateReader(
+		iotest.TimeoutReader(iotest.OneByteReader(
+			strings.NewReader(`{"foo":"bar"}`),
+		)),
+		Options{},
+	)
+	require.Equal(t, iotest.ErrTimeout, err)
+
+	err = parser.ValidateReader(&failingReader{err: readErr}, Options{})
+	require.Equal(t, readErr, err)
+}
+
+type failingReader struct{ err error }
+
+func (r *failingReader) Read([]byte) (int, error) { return 0, r.err }
@@ -0,0 +1,140 @@
+package jsonvalidate
+
+import "github.com/romshark/jsonvalidate-go/internal/stack"
+
+// ValidateAllBytes is similar to ValidateAll
+// but validates the given byte slice
+func (pr *Parser) ValidateAllBytes(s []byte, opts Options) []Err {
+	return pr.validateAll(b2s(s), opts)
+}
+
+// ValidateAll validates a JSON value from the given string
+// returning up to Options.MaxErrors errors, or nil if the value is valid.
+// After an error the rest of the failing array element or object field
+// is skipped and validation resumes at the next ',' or closing bracket
+// of the container the error was found in. Validation stops at the first
+// error outside of any container and at the first exceeded limit
+func (pr *Parser) ValidateAll(s string, opts Options) []Err {
+	return pr.validateAll(s, opts)
+}
+
+func (pr *Parser) validateAll(input string, opts Options) []Err {
+	if err := checkInputLength(input, opts); err.DebugCode != 0 {
+		return []Err{err}
+	}
+
+	var sc scanner
+	sc.init(pr.stackPool, opts, false)
+	defer pr.stackPool.Release(sc.stk)
+
+	var errs []Err
+	s := input
+	for {
+		n, err := sc.scan(s, true)
+		if err.DebugCode == 0 {
+			return errs
+		}
+		err.setPosition(input)
+		errs = append(errs, err)
+		if len(errs) == opts.MaxErrors || isLimitCode(err.DebugCode) {
+			return errs
+		}
+
+		// Skip to the next delimiter
+		s = s[n:]
+		sc.offset += n
+		tail, ok := sc.resync(s)
+		if !ok {
+			return errs
+		}
+		sc.offset += len(s) - len(tail)
+		s = tail
+	}
+}
+
+// resync skips s, the tail of the input beginning at the failing token,
+// up to and including the next ',' or closing bracket of the current
+// container and sets the scanner state accordingly returning the rest
+// of the input. Nested containers and strings are skipped as a whole,
+// false is returned if there's no container or the input ended
+func (sc *scanner) resync(s string) (string, bool) {
+	container := topContainer(sc.stk)
+	if container == 0 {
+		// Nothing to recover to
+		return s, false
+	}
+	depth := 0
+	for len(s) > 0 {
+		switch s[0] {
+		case '"':
+			_, tail, code := scanRawString(s[1:])
+			if code != 0 {
+				return "", false
+			}
+			s = tail
+			continue
+		case '\'':
+			if sc.rules.json5 {
+				_, tail, code := scanRawString5(s[1:], '\'')
+				if code != 0 {
+					return "", false
+				}
+				s = tail
+				continue
+			}
+		case '/':
+			// Brackets and quotes in comments don't count
+			if sc.rules.allowComments && len(s) > 1 && (s[1] == '/' || s[1] == '*') {
+				tail := skipComments(s)
+				if len(tail) == len(s) {
+					// The comment reaches the end of the input
+					return "", false
+				}
+				s = tail
+				continue
+			}
+		case '[', '{':
+			depth++
+		case ']', '}':
+			if depth > 0 {
+				depth--
+				break
+			}
+			// The closing bracket terminates the current container
+			// even if it doesn't match
+			sc.stk.Pop()
+			sc.state = stateNext
+			return s[1:], true
+		case ',':
+			if depth > 0 {
+				break
+			}
+			if container == stack.Array {
+				sc.stk.PushElement()
+				sc.state = stateElement
+			} else {
+				sc.state = stateKey
+			}
+			return s[1:], true
+		}
+		s = s[1:]
+	}
+	return "", false
+}
+
+// isLimitCode returns true for the codes
+// of errors caused by exceeded limits
+func isLimitCode(c ErrorCode) bool {
+	switch c {
+	case ErrMaxDepth,
+		ErrMaxInputBytes,
+		ErrMaxStringBytes,
+		ErrMaxKeyBytes,
+		ErrMaxNumberDigits,
+		ErrMaxObjectKeys,
+		ErrMaxArrayElements,
+		ErrMaxTotalValues:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,146 @@
+package jsonvalidate
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type expectErr struct {
+	code   ErrorCode
+	offset int
+	path   string
+}
+
+func TestValidateAll(t *testing.T) {
+	for _, tt := range []struct {
+		name   string
+		in     string
+		opts   Options
+		expect []expectErr
+	}{
+		{
+			"valid",
+			`{"a": [1, 2, 3]}`, Options{},
+			nil,
+		}, {
+			"single error",
+			`[1, x]`, Options{},
+			[]expectErr{{ErrExpectedValue, 4, `/1`}},
+		}, {
+			"array elements",
+			`[1, x, 3, tru, [01], 5 6]`, Options{},
+			[]expectErr{
+				{ErrExpectedValue, 4, `/1`},
+				{ErrInvalidTrue, 10, `/3`},
+				{ErrLeadingZero, 16, `/4/0`},
+				{ErrExpectedCommaOrBracket, 23, `/5`},
+			},
+		}, {
+			"object fields",
+			`{"a": x, "b" 2, "a": 3, "": 4, "c": {"d": -}}`, Options{},
+			[]expectErr{
+				{ErrExpectedValue, 6, `/a`},
+				{ErrExpectedColon, 13, `/b`},
+				{ErrDuplicateKey, 16, `/a`},
+				{ErrEmptyKey, 24, ``},
+				{ErrExpectedDigit, 42, `/c/d`},
+			},
+		}, {
+			"skip nested containers and strings",
+			`[{"a": x, "b": [1, {"]": "}"}], "c": 2}, [x, [1, 2]], 3]`, Options{},
+			[]expectErr{
+				{ErrExpectedValue, 7, `/0/a`},
+				{ErrExpectedValue, 42, `/1/0`},
+			},
+		}, {
+			"errors inside strings",
+			"[\"a\tb\", \"\\q\", \"\\u12\", 1]", Options{},
+			[]expectErr{
+				{ErrControlCharInString, 3, `/0`},
+				{ErrInvalidEscape, 8, `/1`},
+				{ErrShortUnicodeEscape, 14, `/2`},
+			},
+		}, {
+			"missing closing bracket",
+			`{"a": [1, 2}, "b": x}`, Options{},
+			[]expectErr{
+				{ErrExpectedCommaOrBracket, 11, `/a/1`},
+				{ErrExpectedValue, 19, `/b`},
+			},
+		}, {
+			"unterminated container",
+			`{"a": [1, x`, Options{},
+			[]expectErr{{ErrExpectedValue, 10, `/a/1`}},
+		}, {
+			"unterminated string",
+			`[x, "abc`, Options{},
+			[]expectErr{
+				{ErrExpectedValue, 1, `/0`},
+				{ErrUnterminatedString, 4, `/1`},
+			},
+		}, {
+			"top level",
+			`x, 1, 2`, Options{},
+			[]expectErr{{ErrExpectedValue, 0, ``}},
+		}, {
+			"trailing data",
+			`[x] 1 2`, Options{},
+			[]expectErr{
+				{ErrExpectedValue, 1, `/0`},
+				{ErrTrailingData, 4, ``},
+			},
+		}, {
+			"max errors",
+			`[x, x, x, x]`, Options{MaxErrors: 2},
+			[]expectErr{
+				{ErrExpectedValue, 1, `/0`},
+				{ErrExpectedValue, 4, `/1`},
+			},
+		}, {
+			"limit",
+			`[x, [[1]], x]`, Options{MaxDepth: 2},
+			[]expectErr{
+				{ErrExpectedValue, 1, `/0`},
+				{ErrMaxDepth, 5, `/1/0`},
+			},
+		}, {
+			"input limit",
+			`[x, x, x, x]`, Options{MaxInputBytes: 4},
+			[]expectErr{{ErrMaxInputBytes, 4, ``}},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			check := func(t *testing.T, errs []Err) {
+				if tt.expect == nil {
+					require.Nil(t, errs)
+					return
+				}
+				actual := make([]expectErr, len(errs))
+				for i, err := range errs {
+					actual[i] = expectErr{err.DebugCode, err.Offset, err.Path}
+				}
+				require.Equal(t, tt.expect, actual)
+			}
+			check(t, NewParser(0).ValidateAll(tt.in, tt.opts))
+			check(t, NewParser(0).ValidateAllBytes([]byte(tt.in), tt.opts))
+
+			// The first error is the one returned by Validate
+			errs := NewParser(0).ValidateAll(tt.in, tt.opts)
+			first := NewParser(0).Validate(tt.in, tt.opts)
+			if len(errs) > 0 {
+				require.Equal(t, first, errs[0])
+			} else {
+				require.Zero(t, first.DebugCode)
+			}
+		})
+	}
+}
+
+func TestValidateAllPosition(t *testing.T) {
+	errs := NewParser(0).ValidateAll("[\n  x,\n  1,\n  y\n]", Options{})
+	require.Equal(t, []Err{
+		{DebugCode: ErrExpectedValue, Offset: 4, Line: 2, Column: 3, Path: "/0"},
+		{DebugCode: ErrExpectedValue, Offset: 14, Line: 4, Column: 3, Path: "/2"},
+	}, errs)
+}
@@ -0,0 +1,173 @@
+package jsonvalidate
+
+import (
+	"bytes"
+	"io"
+)
+
+// recordSeparator is the RS control character preceding
+// each text of a JSON text sequence
+const recordSeparator = 0x1E
+
+// SeqRecord is the result of validating a record of a JSON text sequence
+type SeqRecord struct {
+	// Offset is the offset of the record in the input
+	Offset int
+
+	// Length is the length of the record in bytes including
+	// the leading RS and the terminating LF
+	Length int
+
+	// Err is nil if the text of the record is valid, otherwise it's an Err.
+	// Texts that were cut off are reported as ErrTruncatedText
+	Err error
+}
+
+// ValidateJSONSeq validates a JSON text sequence (application/json-seq)
+// as defined by RFC 7464 read from r. Each text is preceded by RS
+// and terminated by LF, the options apply to each text separately.
+// fn is called for each record in order, validation stops as soon as
+// fn returns false. Offsets and positions are relative to the beginning
+// of the input. Consecutive RS are treated as one.
+//
+// A text that is incomplete and not terminated by LF as well as
+// a top-level number, true, false or null not followed by whitespace
+// is reported as ErrTruncatedText, validation continues with the next
+// record. Data preceding the first RS is reported as
+// ErrMissingRecordSeparator.
+// Returns nil once the input is validated or stopped,
+// or the error returned by r
+func (pr *Parser) ValidateJSONSeq(
+	r io.Reader,
+	opts Options,
+	fn func(rec SeqRecord) bool,
+) error {
+	return pr.validateJSONSeq(r, opts, fn, readChunkSize)
+}
+
+func (pr *Parser) validateJSONSeq(
+	r io.Reader,
+	opts Options,
+	fn func(rec SeqRecord) bool,
+	chunkSize int,
+) error {
+	v := pr.NewValidator(opts)
+	buf := make([]byte, chunkSize)
+	pos := startPosition()
+	offset, read := 0, 0
+	separated := false
+	// first is the first non-whitespace byte of the text,
+	// last is the last byte of the text
+	var first, last byte
+
+	// pending returns true if there's a record to report,
+	// a text following RS must not be empty
+	pending := func() bool {
+		return read > offset && (!separated || v.written > 0)
+	}
+
+	// endRecord reports the current record ending at read
+	endRecord := func() bool {
+		rec := SeqRecord{Offset: offset, Length: read - offset}
+		scanned := v.err == nil
+		err := v.Close()
+		switch {
+		case !separated:
+			rec.Err = Err{
+				DebugCode: ErrMissingRecordSeparator,
+				Line:      1,
+				Column:    1,
+			}
+		case err != nil && scanned && last != '\n':
+			// The text ended before it was complete
+			rec.Err = truncatedText(read, pos)
+		case err != nil:
+			rec.Err = err
+		case isScalarStart(first) &&
+			last != ' ' && last != '\t' && last != '\r' && last != '\n':
+			// The value might have been cut off, see RFC 7464 section 2.4
+			rec.Err = truncatedText(read, pos)
+		}
+		return fn(rec)
+	}
+
+	// beginText prepares the validator for the text following an RS
+	beginText := func() {
+		_ = v.Close()
+		v.reset(read, pos)
+		first, last = 0, 0
+	}
+
+	for {
+		n, err := r.Read(buf)
+		p := buf[:n]
+		for len(p) > 0 {
+			t := p
+			i := bytes.IndexByte(p, recordSeparator)
+			if i >= 0 {
+				t = p[:i]
+			}
+			if len(t) > 0 {
+				if first == 0 {
+					if ws := skipWS(b2s(t)); len(ws) > 0 {
+						first = ws[0]
+					}
+				}
+				last = t[len(t)-1]
+				// Errors are kept by the validator until the end of the record
+				_, _ = v.Write(t)
+				pos.advance(b2s(t))
+				read += len(t)
+			}
+			if i < 0 {
+				break
+			}
+
+			if pending() {
+				if !endRecord() {
+					return nil
+				}
+				offset = read
+			}
+			// Consecutive RS begin the same record
+			separated = true
+			pos.advance(b2s(p[i : i+1]))
+			read++
+			beginText()
+			p = p[i+1:]
+		}
+		switch err {
+		case nil:
+		case io.EOF:
+			if pending() {
+				endRecord()
+			}
+			_ = v.Close()
+			return nil
+		default:
+			_ = v.Close()
+			return err
+		}
+	}
+}
+
+// truncatedText returns the error of a text cut off
+// at the given offset and position
+func truncatedText(offset int, p position) Err {
+	return Err{
+		DebugCode: ErrTruncatedText,
+		Offset:    offset,
+		Line:      p.line,
+		Column:    p.column,
+	}
+}
+
+// isScalarStart returns true if c begins a number,
+// true, false, null, NaN or Infinity
+func isScalarStart(c byte) bool {
+	switch c {
+	case '-', '0', '1', '2', '3', '4', '5', '6', '7', '8', '9', 't', 'f', 'n', 'I', 'N':
+		return true
+	}
+	return false
+}
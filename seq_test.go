@@ -0,0 +1,132 @@
+package jsonvalidate
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func validateJSONSeq(
+	t *testing.T,
+	in string,
+	opts Options,
+	chunkSize int,
+	stopAfter int,
+) []SeqRecord {
+	t.Helper()
+	var records []SeqRecord
+	err := NewParser(0).validateJSONSeq(
+		strings.NewReader(in), opts,
+		func(rec SeqRecord) bool {
+			records = append(records, rec)
+			return len(records) != stopAfter
+		},
+		chunkSize,
+	)
+	require.NoError(t, err)
+	return records
+}
+
+func TestValidateJSONSeq(t *testing.T) {
+	for _, tt := range []struct {
+		name   string
+		in     string
+		opts   Options
+		expect []SeqRecord
+	}{
+		{"empty", "", Options{}, nil},
+		{"only_rs", "\x1e\x1e", Options{}, nil},
+		{"single", "\x1e{\"a\":1}\n", Options{}, []SeqRecord{
+			{Offset: 0, Length: 9},
+		}},
+		{"multiple", "\x1e1\n\x1e\"foo\"\n\x1e[true, null]\n", Options{}, []SeqRecord{
+			{Offset: 0, Length: 3},
+			{Offset: 3, Length: 7},
+			{Offset: 10, Length: 14},
+		}},
+		{"consecutive_rs", "\x1e\x1e\x1e[]\n\x1e\x1e{}\n", Options{}, []SeqRecord{
+			{Offset: 0, Length: 6},
+			{Offset: 6, Length: 5},
+		}},
+		{"missing_lf", "\x1e{}\x1e[]", Options{}, []SeqRecord{
+			{Offset: 0, Length: 3},
+			{Offset: 3, Length: 3},
+		}},
+		{"scalar_followed_by_whitespace", "\x1e123 \x1etrue\r\n", Options{}, []SeqRecord{
+			{Offset: 0, Length: 5},
+			{Offset: 5, Length: 7},
+		}},
+		{"truncated_number", "\x1e12\x1e3\n", Options{}, []SeqRecord{
+			{Offset: 0, Length: 3, Err: Err{DebugCode: ErrTruncatedText, Offset: 3, Line: 1, Column: 4}},
+			{Offset: 3, Length: 3},
+		}},
+		{"truncated_literal", "\x1etr\x1enull", Options{}, []SeqRecord{
+			{Offset: 0, Length: 3, Err: Err{DebugCode: ErrTruncatedText, Offset: 3, Line: 1, Column: 4}},
+			{Offset: 3, Length: 5, Err: Err{DebugCode: ErrTruncatedText, Offset: 8, Line: 1, Column: 9}},
+		}},
+		{"truncated_object", "\x1e{\"a\":\n[1,\x1e{\"b\":2}\n", Options{}, []SeqRecord{
+			{Offset: 0, Length: 10, Err: Err{DebugCode: ErrTruncatedText, Offset: 10, Line: 2, Column: 4}},
+			{Offset: 10, Length: 9},
+		}},
+		{"truncated_string", "\x1e\"abc\x1e\"abc\"\n", Options{}, []SeqRecord{
+			{Offset: 0, Length: 5, Err: Err{DebugCode: ErrTruncatedText, Offset: 5, Line: 1, Column: 6}},
+			{Offset: 5, Length: 7},
+		}},
+		{"incomplete_terminated", "\x1e[1,\n\x1e2\n", Options{}, []SeqRecord{
+			{Offset: 0, Length: 5, Err: Err{DebugCode: ErrUnexpectedEOF, Offset: 5, Line: 2, Column: 1, Path: "/1"}},
+			{Offset: 5, Length: 3},
+		}},
+		{"invalid", "\x1e[1,x]\n\x1e{\"a\":1,\"a\":2}\n\x1e\n", Options{}, []SeqRecord{
+			{Offset: 0, Length: 7, Err: Err{DebugCode: ErrExpectedValue, Offset: 4, Line: 1, Column: 5, Path: "/1"}},
+			{Offset: 7, Length: 15, Err: Err{DebugCode: ErrDuplicateKey, Offset: 15, Line: 2, Column: 9, Path: "/a"}},
+			{Offset: 22, Length: 2, Err: Err{DebugCode: ErrEmptyInput, Offset: 24, Line: 4, Column: 1}},
+		}},
+		{"two_values", "\x1e1 2\n", Options{}, []SeqRecord{
+			{Offset: 0, Length: 5, Err: Err{DebugCode: ErrTrailingData, Offset: 3, Line: 1, Column: 4}},
+		}},
+		{"missing_rs", "{}\n\x1e{}\n", Options{}, []SeqRecord{
+			{Offset: 0, Length: 3, Err: Err{DebugCode: ErrMissingRecordSeparator, Line: 1, Column: 1}},
+			{Offset: 3, Length: 4},
+		}},
+		{"expect_document", "\x1e{}\n\x1e[]\n", Options{ExpectDocument: true}, []SeqRecord{
+			{Offset: 0, Length: 4},
+			{Offset: 4, Length: 4, Err: Err{DebugCode: ErrExpectedObject, Offset: 5, Line: 2, Column: 2}},
+		}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, chunkSize := range readerChunkSizes {
+				records := validateJSONSeq(t, tt.in, tt.opts, chunkSize, 0)
+				require.Equal(t, tt.expect, records, "chunk size: %d", chunkSize)
+			}
+		})
+	}
+}
+
+func TestValidateJSONSeqStop(t *testing.T) {
+	in := "\x1e1\n\x1e[\n\x1e3\n"
+	for _, chunkSize := range readerChunkSizes {
+		records := validateJSONSeq(t, in, Options{}, chunkSize, 2)
+		require.Len(t, records, 2, "chunk size: %d", chunkSize)
+		require.Error(t, records[1].Err)
+	}
+}
+
+func TestValidateJSONSeqReadError(t *testing.T) {
+	readErr := errors.New("read failed")
+	var records []SeqRecord
+	err := NewParser(0).ValidateJSONSeq(
+		io.MultiReader(
+			strings.NewReader("\x1e1\n\x1e2\n"), &failingReader{err: readErr},
+		),
+		Options{},
+		func(rec SeqRecord) bool {
+			records = append(records, rec)
+			return true
+		},
+	)
+	require.Equal(t, readErr, err)
+	require.Equal(t, []SeqRecord{{Offset: 0, Length: 3}}, records)
+}
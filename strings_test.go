@@ -0,0 +1,139 @@
+package jsonvalidate
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateUTF8(t *testing.T) {
+	for _, tt := range []struct {
+		name   string
+		in     string
+		offset int
+		path   string
+	}{
+		{"truncated sequence", "[\"abc\xc3\"]", 5, `/0`},
+		{"invalid start byte", "[\"\xff\"]", 2, `/0`},
+		{"unexpected continuation byte", "[\"\u00e4\x80\"]", 4, `/0`},
+		{"overlong encoding", "[\"a\xc0\xafb\"]", 3, `/0`},
+		{"overlong encoding_3 bytes", "[\"\xe0\x80\xaf\"]", 2, `/0`},
+		{"encoded surrogate", "[\"\xed\xa0\x80\"]", 2, `/0`},
+		{"beyond U+10FFFF", "[\"\xf4\x90\x80\x80\"]", 2, `/0`},
+		{"key", "{\"a\": {\"\xe2\x82\": 1}}", 8, `/a`},
+		{"key_escaped", "{\"\\n\xff\": 1}", 4, ``},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := Options{ValidateUTF8: true}
+			err := NewParser(0).Validate(tt.in, opts)
+			require.Equal(t, ErrInvalidUTF8, err.DebugCode)
+			require.Equal(t, tt.offset, err.Offset)
+			require.Equal(t, tt.path, err.Path)
+
+			v := NewParser(0).NewValidator(opts)
+			require.Equal(t, err, writeParts(v, tt.in, 1))
+
+			// Invalid UTF-8 is accepted by default
+			require.Zero(t, NewParser(0).Validate(tt.in, Options{}).DebugCode)
+		})
+	}
+}
+
+func TestValidateUTF8Valid(t *testing.T) {
+	opts := Options{ValidateUTF8: true}
+	for _, in := range []string{
+		`"ascii"`,
+		`"ä€😀"`,
+		`{"ключ": "значение"}`,
+		"\"\xef\xbf\xbd\"", // U+FFFD itself
+		`"\ud800"`,         // Escaped surrogates aren't UTF-8
+	} {
+		require.Zero(t, NewParser(0).Validate(in, opts).DebugCode, in)
+	}
+}
+
+func TestControlCharInString(t *testing.T) {
+	for _, tt := range []struct {
+		name   string
+		in     string
+		offset int
+		path   string
+	}{
+		{"tab", "\"a\tb\"", 2, ``},
+		{"newline", "[\"abc\", \"a\nb\"]", 10, `/1`},
+		{"carriage return", "{\"a\": \"\r\"}", 7, `/a`},
+		{"null byte", "\"\x00\"", 1, ``},
+		{"unit separator", "\"\x1f\"", 1, ``},
+		{"after escape sequence", "\"\\n\t\"", 3, ``},
+		{"long", "\"0123456789abcdef01234\x01\"", 22, ``},
+		{"long_escaped", "\"0123456789\\\"abcdef01234\x7f\x01\"", 25, ``},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			err := NewParser(0).Validate(tt.in, Options{})
+			require.Equal(t, ErrControlCharInString, err.DebugCode)
+			require.Equal(t, tt.offset, err.Offset)
+			require.Equal(t, tt.path, err.Path)
+
+			v := NewParser(0).NewValidator(Options{})
+			require.Equal(t, err, writeParts(v, tt.in, 1))
+
+			// Control characters are accepted when explicitly allowed
+			opts := Options{AllowControlChars: true}
+			require.Zero(t, NewParser(0).Validate(tt.in, opts).DebugCode)
+		})
+	}
+
+	// Keys never allow control characters
+	opts := Options{AllowControlChars: true}
+	err := NewParser(0).Validate("{\"a\tb\": 1}", opts)
+	require.Equal(t, ErrControlCharInKey, err.DebugCode)
+
+	// Escaped control characters and DEL are fine
+	require.Zero(t, NewParser(0).Validate(`"\t\n\u0000`+"\x7f\"", Options{}).DebugCode)
+}
+
+func TestValidateSurrogates(t *testing.T) {
+	for _, tt := range []struct {
+		name   string
+		in     string
+		code   ErrorCode
+		offset int
+		path   string
+	}{
+		{"lone high", `"\uD800"`, ErrLoneHighSurrogate, 1, ``},
+		{"lone high_at end", `["ab\ud83d"]`, ErrLoneHighSurrogate, 4, `/0`},
+		{"high followed by character", `"\uD83Dx\uDE00"`, ErrLoneHighSurrogate, 1, ``},
+		{"high followed by escape", `"\uD83D\n"`, ErrLoneHighSurrogate, 1, ``},
+		{"high followed by high", `"\uD83D\uD83D\uDE00"`, ErrLoneHighSurrogate, 1, ``},
+		{"high followed by non-surrogate", `"\uD83DA"`, ErrLoneHighSurrogate, 1, ``},
+		{"lone low", `"\uDC00"`, ErrLoneLowSurrogate, 1, ``},
+		{"lone low_after pair", `"a\uD83D\uDE00\uDE00"`, ErrLoneLowSurrogate, 14, ``},
+		{"low followed by high", `"\uDE00\uD83D"`, ErrLoneLowSurrogate, 1, ``},
+		{"key", `{"a": 1, "\udfff": 2}`, ErrLoneLowSurrogate, 10, ``},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := Options{ValidateSurrogates: true}
+			err := NewParser(0).Validate(tt.in, opts)
+			require.Equal(t, tt.code, err.DebugCode)
+			require.Equal(t, tt.offset, err.Offset)
+			require.Equal(t, tt.path, err.Path)
+
+			v := NewParser(0).NewValidator(opts)
+			require.Equal(t, err, writeParts(v, tt.in, 1))
+
+			// Lone surrogates are accepted by default
+			require.Zero(t, NewParser(0).Validate(tt.in, Options{}).DebugCode)
+		})
+	}
+
+	opts := Options{ValidateSurrogates: true}
+	for _, in := range []string{
+		`"\uD83D\uDE00"`,
+		`"\ud83d\ude00 \uDBFF\uDFFF"`,
+		`"\\uD800"`,
+		`"\uD7FF\uE000"`,
+		`{"\uD83D\uDE00": "\u00e4"}`,
+	} {
+		require.Zero(t, NewParser(0).Validate(in, opts).DebugCode, in)
+	}
+}
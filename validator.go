@@ -0,0 +1,228 @@
+package jsonvalidate
+
+import (
+	"errors"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/romshark/jsonvalidate-go/internal/stack"
+)
+
+var errValidatorClosed = errors.New("jsonvalidate: write to closed validator")
+
+// Validator incrementally validates a JSON value written to it
+// in consecutive parts. The scanner state is preserved between writes
+// and only the token that's currently being scanned is buffered.
+// A Validator must be closed after the last write
+type Validator struct {
+	stackPool *stack.Pool
+	sc        scanner
+	pos       position // Position of the next byte to be scanned
+	base      int      // Offset of the value in the input
+	written   int
+	pending   []byte
+	err       error
+	closed    bool
+}
+
+// NewValidator creates a new incremental validator
+func (pr *Parser) NewValidator(opts Options) *Validator {
+	v := &Validator{
+		stackPool: pr.stackPool,
+		sc:        scanner{opts: opts},
+	}
+	v.reset(0, startPosition())
+	return v
+}
+
+// reset prepares the closed validator for validating another value
+// beginning at the given offset and position of the input
+func (v *Validator) reset(offset int, pos position) {
+	// Written parts don't outlive the write,
+	// keys must not refer to them
+	v.sc.init(v.stackPool, v.sc.opts, true)
+	v.sc.offset = offset
+	// Only the beginning of the input may have a byte order mark
+	v.sc.bom = offset == 0
+	v.pos = pos
+	v.base = offset
+	v.written = 0
+	v.pending = v.pending[:0]
+	v.err = nil
+	v.closed = false
+}
+
+// Write scans p returning an Err as soon as p is found to be invalid,
+// in which case n is the number of bytes preceding the error in p.
+// Once an error was returned all subsequent writes fail with the same error.
+// Strings are validated as they arrive, numbers, comments and JSON5
+// identifiers that span several writes are rescanned only when
+// the written part might end them or turn them invalid
+func (v *Validator) Write(p []byte) (n int, err error) {
+	if v.err != nil {
+		return 0, v.err
+	}
+	if v.closed {
+		return 0, errValidatorClosed
+	}
+	start := v.written
+	v.written += len(p)
+
+	if limit := v.sc.opts.MaxInputBytes; limit > 0 && v.written > limit {
+		// Fail without scanning the part within the limit
+		// just like Validate does
+		verr := Err{DebugCode: ErrMaxInputBytes, Offset: v.base + limit}
+		v.fail(&verr, append(v.pending, p...))
+		return limit - start, verr
+	}
+
+	in := p
+	if len(v.pending) > 0 {
+		extends := v.sc.extendsToken(b2s(v.pending), b2s(p))
+		v.pending = append(v.pending, p...)
+		if extends {
+			// The token is still incomplete and valid
+			return len(p), nil
+		}
+		in = v.pending
+	}
+
+	consumed, verr := v.sc.scan(b2s(in), false)
+	if verr.DebugCode != 0 {
+		v.fail(&verr, in)
+		n = verr.Offset - v.base - start
+		if n < 0 {
+			n = 0
+		}
+		return n, verr
+	}
+	v.sc.offset += consumed
+	v.pos.advance(b2s(in[:consumed]))
+
+	// Keep the incomplete token for the next write
+	if consumed > 0 || len(v.pending) == 0 {
+		v.pending = append(v.pending[:0], in[consumed:]...)
+	}
+	return len(p), nil
+}
+
+// Close finishes validation and returns an Err if the input is invalid
+// or ended prematurely. Close returns the error previously returned
+// by Write, if any
+func (v *Validator) Close() error {
+	if v.closed || v.err != nil {
+		v.closed = true
+		return v.err
+	}
+	v.closed = true
+
+	_, verr := v.sc.scan(b2s(v.pending), true)
+	if verr.DebugCode != 0 {
+		v.fail(&verr, v.pending)
+		return verr
+	}
+	v.release()
+	return nil
+}
+
+// fail sets the position of err found in the given part of the input
+// and makes it the result of all subsequent calls
+func (v *Validator) fail(err *Err, in []byte) {
+	p := v.pos.at(b2s(in[:err.Offset-v.sc.offset]))
+	err.Line, err.Column = p.line, p.column
+	v.err = *err
+	v.pending = nil
+	v.release()
+}
+
+// release returns the stack to the pool
+func (v *Validator) release() {
+	if v.sc.stk != nil {
+		v.stackPool.Release(v.sc.stk)
+		v.sc.stk = nil
+	}
+}
+
+// resumeString validates the part of the string that scanning was
+// suspended in, which begins input, that arrived since the last scan.
+// Unless done is set the string is scanned from its beginning since it's
+// complete, exceeds the limit or the input is final.
+// If done is set n and err are the results of scan
+func (sc *scanner) resumeString(input string, final bool) (n int, err Err, done bool) {
+	maxLen, state := sc.opts.MaxStringBytes, stateNext
+	if sc.state != stateValue {
+		maxLen, state = sc.opts.MaxKeyBytes, sc.state
+	}
+	raw := input[1:]
+	var code ErrorCode
+	if sc.rules.json5 {
+		_, _, code = scanRawString5(raw[sc.checked:], input[0])
+	} else {
+		_, _, code = scanRawString(raw[sc.checked:])
+	}
+	if code == 0 || final || maxLen > 0 && len(raw) > maxLen {
+		sc.inString, sc.checked = false, 0
+		return 0, Err{}, false
+	}
+	sc.checked, n, err = sc.checkOpenString(
+		input, input, code, maxLen, state, sc.checked,
+	)
+	return n, err, true
+}
+
+// extendsToken returns true if p merely extends the incomplete token
+// scanning was suspended at the beginning of, so that the token
+// neither ends nor turns invalid within p. Only the numbers, comments
+// and JSON5 identifiers that follow a valid prefix are recognized
+func (sc *scanner) extendsToken(token, p string) bool {
+	switch {
+	case len(p) == 0:
+		return true
+	case sc.inString:
+		// Strings are validated as they arrive
+		return false
+	case len(token) > 1 && token[0] == '/' && token[1] == '*':
+		// Block comment
+		if token[len(token)-1] == '*' && p[0] == '/' {
+			return false
+		}
+		return !strings.Contains(p, "*/")
+	case len(token) > 1 && token[0] == '/':
+		// Line comment
+		return strings.IndexByte(p, '\n') < 0
+	case sc.state == stateValue && strings.IndexByte("+-.0123456789", token[0]) >= 0:
+		// Number, digits are valid after its prefix
+		// unless they follow a sign or a leading zero
+		t := strings.TrimLeft(token, "+-")
+		if t == "" || t == "0" {
+			return false
+		}
+		if max := sc.opts.MaxNumberDigits; max > 0 && len(token)+len(p) > max {
+			return false
+		}
+		isDigit := func(c byte) bool { return c >= '0' && c <= '9' }
+		if sc.rules.json5 && len(t) > 1 && t[0] == '0' && (t[1] == 'x' || t[1] == 'X') {
+			isDigit = isHexDigit
+		}
+		return strings.IndexFunc(p, func(r rune) bool {
+			return r >= utf8.RuneSelf || !isDigit(byte(r))
+		}) < 0
+	case sc.rules.json5 && (sc.state == stateKey || sc.state == stateFirstKey):
+		// Identifier key, unless it ends within
+		// an escape sequence or a multi-byte character
+		end := token
+		if len(end) > len(`\u0000`)-1 {
+			end = end[len(end)-len(`\u0000`)+1:]
+		}
+		if strings.IndexByte(end, '\\') >= 0 || end[len(end)-1] >= utf8.RuneSelf {
+			return false
+		}
+		if max := sc.opts.MaxKeyBytes; max > 0 && len(token)+len(p) > max {
+			return false
+		}
+		return strings.IndexFunc(p, func(r rune) bool {
+			return r >= utf8.RuneSelf || !isIdentifierRune(r, false)
+		}) < 0
+	}
+	return false
+}
@@ -0,0 +1,325 @@
+package jsonvalidate
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// writeParts writes in to v in parts of the given size
+func writeParts(v *Validator, in string, partSize int) error {
+	for len(in) > 0 {
+		n := partSize
+		if n > len(in) {
+			n = len(in)
+		}
+		if _, err := v.Write([]byte(in[:n])); err != nil {
+			return err
+		}
+		in = in[n:]
+	}
+	return v.Close()
+}
+
+func TestValidatorValid(t *testing.T) {
+	inputs := append(validValues(), validDocuments()...)
+	for _, tt := range inputs {
+		for _, partSize := range readerChunkSizes {
+			t.Run(tt.Name, func(t *testing.T) {
+				v := NewParser(0).NewValidator(Options{})
+				err := writeParts(v, tt.Source, partSize)
+				require.NoError(t, err, "part size: %d", partSize)
+			})
+		}
+	}
+}
+
+func TestValidatorInvalid(t *testing.T) {
+	for _, in := range []string{
+		``,
+		`[`,
+		`{"foo":"bar"`,
+		`"unterminated`,
+		`fals`,
+		`1e+`,
+		`[1] 2`,
+		`{"x": 1, "x": 2}`,
+		`["abc", "def\q"]`,
+		mediumInvalid,
+	} {
+		expected := NewParser(0).Validate(in, Options{})
+		require.NotZero(t, expected.DebugCode)
+
+		for _, partSize := range readerChunkSizes {
+			t.Run(in, func(t *testing.T) {
+				v := NewParser(0).NewValidator(Options{})
+				err := writeParts(v, in, partSize)
+				require.Equal(t, expected, err, "part size: %d", partSize)
+			})
+		}
+	}
+}
+
+func TestValidatorFailEarly(t *testing.T) {
+	v := NewParser(0).NewValidator(Options{})
+
+	n, err := v.Write([]byte(`{"foo":[1,2`))
+	require.NoError(t, err)
+	require.Equal(t, 11, n)
+
+	// The invalid byte is reported by the write delivering it
+	expected := Err{
+		DebugCode: ErrExpectedCommaOrBracket,
+		Offset:    14,
+		Line:      1,
+		Column:    15,
+		Path:      "/foo/2",
+	}
+	n, err = v.Write([]byte(`,3 x`))
+	require.Equal(t, expected, err)
+	require.Equal(t, 3, n)
+
+	// Errors are sticky
+	n, err = v.Write([]byte(`]}`))
+	require.Equal(t, expected, err)
+	require.Zero(t, n)
+	require.Equal(t, expected, v.Close())
+}
+
+func TestValidatorPrematureEnd(t *testing.T) {
+	v := NewParser(0).NewValidator(Options{})
+	_, err := v.Write([]byte(`{"foo":"ba`))
+	require.NoError(t, err)
+	require.Equal(t, Err{DebugCode: ErrUnterminatedString, Offset: 7, Line: 1, Column: 8, Path: "/foo"}, v.Close())
+}
+
+func TestValidatorWriteAfterClose(t *testing.T) {
+	v := NewParser(0).NewValidator(Options{})
+	_, err := v.Write([]byte(`{}`))
+	require.NoError(t, err)
+	require.NoError(t, v.Close())
+	require.NoError(t, v.Close())
+
+	_, err = v.Write([]byte(` `))
+	require.Equal(t, errValidatorClosed, err)
+}
+
+func TestValidatorLongToken(t *testing.T) {
+	// Long tokens written byte by byte
+	long := strings.Repeat("1", 1024*256)
+	for _, tt := range []struct {
+		name string
+		in   string
+		opts Options
+	}{
+		{"string", `["` + long + `"]`, Options{}},
+		{"number", `[` + long + `]`, Options{}},
+		{"number_fraction", `[` + long + `.` + long + `e-` + long + `]`, Options{}},
+		{"block_comment", `[/*` + long + `*/]`, Options{AllowComments: true}},
+		{"line_comment", "[//" + long + "\n]", Options{AllowComments: true}},
+		{"json5_hex", `[0xf` + long + `F]`, Options{Dialect: JSON5}},
+		{"json5_identifier", `{a` + long + `: 1}`, Options{Dialect: JSON5}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			v := NewParser(0).NewValidator(tt.opts)
+			require.NoError(t, writeParts(v, tt.in, 1))
+		})
+	}
+
+	v := NewParser(0).NewValidator(Options{})
+	in := `["` + long + `"]`
+	require.Equal(t,
+		Err{DebugCode: ErrUnterminatedString, Offset: 1, Line: 1, Column: 2, Path: "/0"},
+		writeParts(v, in[:len(in)-2], 1),
+	)
+}
+
+func TestValidatorLongTokenFailEarly(t *testing.T) {
+	// The write following a long token reports the error it contains
+	long := strings.Repeat("1", 1024*70)
+	for _, tt := range []struct {
+		name   string
+		token  string
+		write  string
+		opts   Options
+		expect ErrorCode
+	}{
+		{"number", `[` + long, `x]`, Options{}, ErrExpectedCommaOrBracket},
+		{"number_fraction", `[` + long, `.x]`, Options{}, ErrExpectedFractionDigit},
+		{"number_exponent", `[` + long + `.1`, `e1.`, Options{}, ErrExpectedCommaOrBracket},
+		{"number_max_digits", `[` + long, `11]`,
+			Options{MaxNumberDigits: len(long) + 1}, ErrMaxNumberDigits},
+		{"block_comment", `[/*` + long, `*/ x]`,
+			Options{AllowComments: true}, ErrExpectedValue},
+		{"block_comment_cut_off", `[/*` + long + `*`, `/ x]`,
+			Options{AllowComments: true}, ErrExpectedValue},
+		{"line_comment", `[//` + long, "\n x]",
+			Options{AllowComments: true}, ErrExpectedValue},
+		{"json5_hex", `[0x` + long, `g]`, Options{Dialect: JSON5}, ErrExpectedCommaOrBracket},
+		{"json5_identifier", `{a` + long, `-: 1}`, Options{Dialect: JSON5}, ErrExpectedColon},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			expect := NewParser(0).Validate(tt.token+tt.write, tt.opts)
+			require.Equal(t, tt.expect, expect.DebugCode)
+
+			v := NewParser(0).NewValidator(tt.opts)
+			_, err := v.Write([]byte(tt.token))
+			require.NoError(t, err)
+			n, err := v.Write([]byte(tt.write))
+			require.Equal(t, expect, err)
+			expectN := expect.Offset - len(tt.token)
+			if expectN < 0 {
+				expectN = 0
+			}
+			require.Equal(t, expectN, n)
+		})
+	}
+}
+
+func TestValidatorFailEarlyInString(t *testing.T) {
+	for _, tt := range []struct {
+		name   string
+		writes []string
+		opts   Options
+		failAt int // Index of the failing write, -1 if valid
+		expect Err
+	}{
+		{"invalid_escape", []string{`["abc\q`, `"]`}, Options{}, 0,
+			Err{DebugCode: ErrInvalidEscape, Offset: 1, Line: 1, Column: 2, Path: "/0"}},
+		{"invalid_escape_cut_off", []string{`["abc\`, `q"]`}, Options{}, 1,
+			Err{DebugCode: ErrInvalidEscape, Offset: 1, Line: 1, Column: 2, Path: "/0"}},
+		{"invalid_unicode_escape_cut_off", []string{`["a\u12`, `x4"]`}, Options{}, 1,
+			Err{DebugCode: ErrInvalidUnicodeEscape, Offset: 1, Line: 1, Column: 2, Path: "/0"}},
+		{"control_char", []string{`["abc`, "\x01", `"]`}, Options{}, 1,
+			Err{DebugCode: ErrControlCharInString, Offset: 5, Line: 1, Column: 6, Path: "/0"}},
+		{"control_char_after_escape", []string{`["a\n`, "b\x01c", `"]`}, Options{}, 1,
+			Err{DebugCode: ErrControlCharInString, Offset: 6, Line: 1, Column: 7, Path: "/0"}},
+		{"control_char_allowed", []string{`["a`, "\x01", `\q`, `"]`},
+			Options{AllowControlChars: true}, 2,
+			Err{DebugCode: ErrInvalidEscape, Offset: 1, Line: 1, Column: 2, Path: "/0"}},
+		{"key_control_char", []string{`{"a\n`, "\x01", `":1}`}, Options{}, 1,
+			Err{DebugCode: ErrControlCharInKey, Offset: 1, Line: 1, Column: 2}},
+		{"key_invalid_escape", []string{`{"a`, `\q`, `":1}`}, Options{}, 1,
+			Err{DebugCode: ErrInvalidEscape, Offset: 1, Line: 1, Column: 2}},
+		{"json5_line_terminator", []string{`['a`, "\n", `']`}, Options{Dialect: JSON5}, 1,
+			Err{DebugCode: ErrControlCharInString, Offset: 3, Line: 1, Column: 4, Path: "/0"}},
+		{"json5_octal_escape_cut_off", []string{`['\0`, `1']`}, Options{Dialect: JSON5}, 1,
+			Err{DebugCode: ErrInvalidEscape, Offset: 1, Line: 1, Column: 2, Path: "/0"}},
+		{"max_string_bytes", []string{`["ab\q`, `cdef"]`}, Options{MaxStringBytes: 4}, 0,
+			Err{DebugCode: ErrInvalidEscape, Offset: 1, Line: 1, Column: 2, Path: "/0"}},
+		{"valid_escapes_cut_off", []string{`["a\`, `n\u00`, `41\`, `"`, `"]`}, Options{}, -1,
+			Err{}},
+		{"json5_line_continuation_cut_off", []string{`['a\` + "\r", "\nb']"},
+			Options{Dialect: JSON5}, -1, Err{}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			v := NewParser(0).NewValidator(tt.opts)
+			for i, w := range tt.writes {
+				_, err := v.Write([]byte(w))
+				if i < tt.failAt || tt.failAt < 0 {
+					require.NoError(t, err, "write %d", i)
+					continue
+				}
+				require.Equal(t, tt.expect, err, "write %d", i)
+			}
+			if tt.failAt < 0 {
+				require.NoError(t, v.Close())
+			}
+
+			// Validate reports the same error
+			in := strings.Join(tt.writes, "")
+			require.Equal(t, tt.expect, NewParser(0).Validate(in, tt.opts))
+			for _, partSize := range readerChunkSizes {
+				v := NewParser(0).NewValidator(tt.opts)
+				err := writeParts(v, in, partSize)
+				if tt.failAt < 0 {
+					require.NoError(t, err, "part size: %d", partSize)
+					continue
+				}
+				require.Equal(t, tt.expect, err, "part size: %d", partSize)
+			}
+		})
+	}
+}
+
+func TestValidateInvalidInUnterminatedString(t *testing.T) {
+	// Invalid bytes precede the missing end of the string
+	for _, tt := range []struct {
+		name   string
+		in     string
+		opts   Options
+		expect Err
+	}{
+		{"invalid_escape", `["\q`, Options{},
+			Err{DebugCode: ErrInvalidEscape, Offset: 1, Line: 1, Column: 2, Path: "/0"}},
+		{"control_char", "[\"a\x01", Options{},
+			Err{DebugCode: ErrControlCharInString, Offset: 3, Line: 1, Column: 4, Path: "/0"}},
+		{"cut_off_escape", `["a\u12`, Options{},
+			Err{DebugCode: ErrUnterminatedString, Offset: 1, Line: 1, Column: 2, Path: "/0"}},
+		{"beyond_limit", `["abc\q`, Options{MaxStringBytes: 3},
+			Err{DebugCode: ErrMaxStringBytes, Offset: 5, Line: 1, Column: 6, Path: "/0"}},
+		{"control_char_before_escape", "\"\x01\\q\"", Options{},
+			Err{DebugCode: ErrControlCharInString, Offset: 1, Line: 1, Column: 2}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.expect, NewParser(0).Validate(tt.in, tt.opts))
+		})
+	}
+}
+
+func TestValidatorLongStringFailEarly(t *testing.T) {
+	// Long strings are validated as they arrive
+	const size = 1024 * 192
+	v := NewParser(0).NewValidator(Options{})
+	x := []byte("x")
+	_, err := v.Write([]byte(`["`))
+	require.NoError(t, err)
+	for i := 0; i < size; i++ {
+		_, err = v.Write(x)
+		require.NoError(t, err)
+	}
+	n, err := v.Write([]byte("\x01"))
+	require.Zero(t, n)
+	require.Equal(t, Err{
+		DebugCode: ErrControlCharInString,
+		Offset:    size + 2,
+		Line:      1,
+		Column:    size + 3,
+		Path:      "/0",
+	}, err)
+}
+
+func TestValidatorTokenParts(t *testing.T) {
+	// Tokens continued by a part that turns them invalid
+	for _, tt := range []struct {
+		name  string
+		parts []string
+		opts  Options
+	}{
+		{"leading_zero", []string{`[0`, `1]`}, Options{}},
+		{"leading_zero_negative", []string{`[-`, `01]`}, Options{}},
+		{"leading_zero_json5", []string{`[+`, `01]`}, Options{Dialect: JSON5}},
+		{"bom", []string{"\xef", "1"}, Options{BOM: Skip}},
+		{"json5_whitespace", []string{"[\xe2", "1]"}, Options{Dialect: JSON5}},
+		{"json5_identifier_escape", []string{`{a\u00`, `zz: 1}`}, Options{Dialect: JSON5}},
+		{"json5_identifier_rune", []string{"{a\xc3", "a: 1}"}, Options{Dialect: JSON5}},
+		{"comment_start", []string{`[/`, `1]`}, Options{AllowComments: true}},
+		{"max_number_digits", []string{`[12`, `34]`}, Options{MaxNumberDigits: 3}},
+		{"max_key_bytes", []string{`{ab`, `cd: 1}`}, Options{Dialect: JSON5, MaxKeyBytes: 3}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			expect := NewParser(0).Validate(strings.Join(tt.parts, ""), tt.opts)
+			require.NotZero(t, expect.DebugCode)
+
+			v := NewParser(0).NewValidator(tt.opts)
+			var err error
+			for _, p := range tt.parts {
+				if _, err = v.Write([]byte(p)); err != nil {
+					break
+				}
+			}
+			require.Equal(t, expect, err)
+		})
+	}
+}
@@ -0,0 +1,37 @@
+package jsonvalidate
+
+// Range is the byte range [Start, End) of a value in the input
+type Range struct {
+	Start, End int
+}
+
+// ValueRangesBytes is similar to ValueRanges
+// but validates the given byte slice
+func (pr *Parser) ValueRangesBytes(s []byte, opts Options) ([]Range, Err) {
+	return pr.valueRanges(b2s(s), opts)
+}
+
+// ValueRanges is similar to Validate but also returns the byte ranges
+// of the top-level values, excluding the whitespace around them.
+// Unless Options.AllowMultipleValues is set there's at most one.
+// In case of an error the ranges of the values preceding
+// the failing one are returned
+func (pr *Parser) ValueRanges(s string, opts Options) ([]Range, Err) {
+	return pr.valueRanges(s, opts)
+}
+
+func (pr *Parser) valueRanges(input string, opts Options) ([]Range, Err) {
+	if err := checkInputLength(input, opts); err.DebugCode != 0 {
+		return nil, err
+	}
+
+	var sc scanner
+	sc.init(pr.stackPool, opts, false)
+	defer pr.stackPool.Release(sc.stk)
+	sc.trackRanges = true
+	_, err := sc.scan(input, true)
+	if err.DebugCode != 0 {
+		err.setPosition(input)
+	}
+	return sc.ranges, err
+}
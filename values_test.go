@@ -0,0 +1,103 @@
+package jsonvalidate
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAllowMultipleValues(t *testing.T) {
+	opts := Options{AllowMultipleValues: true}
+	for _, tt := range []struct {
+		name   string
+		in     string
+		expect []Range
+	}{
+		{"single", `{"a":1}`, []Range{{0, 7}}},
+		{"objects", `{} {}`, []Range{{0, 2}, {3, 5}}},
+		{"adjacent", `{}[]"a""b"`, []Range{{0, 2}, {2, 4}, {4, 7}, {7, 10}}},
+		{"scalars", " 1\n2\ttrue null\r\n-0.5e3 ", []Range{
+			{1, 2}, {3, 4}, {5, 9}, {10, 14}, {16, 22},
+		}},
+		{"literal_followed_by_value", `true[1]null{}`, []Range{
+			{0, 4}, {4, 7}, {7, 11}, {11, 13},
+		}},
+		{"nested", `{"a":[1,{"b":[]}]} [[],{}]`, []Range{{0, 18}, {19, 26}}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Zero(t, NewParser(0).Validate(tt.in, opts))
+
+			ranges, err := NewParser(0).ValueRanges(tt.in, opts)
+			require.Zero(t, err)
+			require.Equal(t, tt.expect, ranges)
+
+			for _, partSize := range readerChunkSizes {
+				v := NewParser(0).NewValidator(opts)
+				require.NoError(t, writeParts(v, tt.in, partSize), "part size: %d", partSize)
+			}
+		})
+	}
+}
+
+func TestAllowMultipleValuesInvalid(t *testing.T) {
+	for _, tt := range []struct {
+		name   string
+		in     string
+		opts   Options
+		expect Err
+		ranges []Range
+	}{
+		{
+			"disabled", `{} {}`, Options{},
+			Err{DebugCode: ErrTrailingData, Offset: 3, Line: 1, Column: 4},
+			[]Range{{0, 2}},
+		},
+		{
+			"comma", `true,false`, Options{AllowMultipleValues: true},
+			Err{DebugCode: ErrExpectedValue, Offset: 4, Line: 1, Column: 5},
+			[]Range{{0, 4}},
+		},
+		{
+			"empty", ` `, Options{AllowMultipleValues: true},
+			Err{DebugCode: ErrEmptyInput, Offset: 1, Line: 1, Column: 2},
+			nil,
+		},
+		{
+			"incomplete", `{} [1,`, Options{AllowMultipleValues: true},
+			Err{DebugCode: ErrUnexpectedEOF, Offset: 6, Line: 1, Column: 7, Path: "/1"},
+			[]Range{{0, 2}},
+		},
+		{
+			"invalid_second", "1\n{\"a\":1,\"a\":2}", Options{AllowMultipleValues: true},
+			Err{DebugCode: ErrDuplicateKey, Offset: 9, Line: 2, Column: 8, Path: "/a"},
+			[]Range{{0, 1}},
+		},
+		{
+			"expect_document", `{"a":1} []`,
+			Options{AllowMultipleValues: true, ExpectDocument: true},
+			Err{DebugCode: ErrExpectedObject, Offset: 8, Line: 1, Column: 9},
+			[]Range{{0, 7}},
+		},
+		{
+			"max_total_values", `1 2 3`,
+			Options{AllowMultipleValues: true, MaxTotalValues: 2},
+			Err{DebugCode: ErrMaxTotalValues, Offset: 4, Line: 1, Column: 5},
+			[]Range{{0, 1}, {2, 3}},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			err := NewParser(0).Validate(tt.in, tt.opts)
+			require.Equal(t, tt.expect, err)
+
+			ranges, err := NewParser(0).ValueRanges(tt.in, tt.opts)
+			require.Equal(t, tt.expect, err)
+			require.Equal(t, tt.ranges, ranges)
+
+			for _, partSize := range readerChunkSizes {
+				v := NewParser(0).NewValidator(tt.opts)
+				err := writeParts(v, tt.in, partSize)
+				require.Equal(t, tt.expect, err, "part size: %d", partSize)
+			}
+		})
+	}
+}